@@ -0,0 +1,204 @@
+package pogodoc
+
+import (
+	"context"
+
+	"github.com/Pogodoc/pogodoc-go/client/core"
+	"github.com/Pogodoc/pogodoc-go/client/documents"
+	"github.com/Pogodoc/pogodoc-go/client/option"
+	"github.com/Pogodoc/pogodoc-go/client/templates"
+)
+
+//go:generate mockery
+
+// TemplatesAPI is the set of template endpoints of the Pogodoc API,
+// implemented by the generated templates client.
+// Substitute it with NewPogodocClient to test code using PogodocClient without the API.
+type TemplatesAPI interface {
+	// InitializeTemplateCreation returns the ID of a new template and a presigned URL to upload its ZIP archive to.
+	InitializeTemplateCreation(ctx context.Context, opts ...RequestOption) (*InitializeTemplateCreationResponse, error)
+	// SaveCreatedTemplate saves the info and previews of a template created with InitializeTemplateCreation.
+	SaveCreatedTemplate(ctx context.Context, templateId string, request *SaveCreatedTemplateRequest, opts ...RequestOption) error
+	// UpdateTemplate replaces the content of a template with the content uploaded under request.ContentId.
+	UpdateTemplate(ctx context.Context, templateId string, request *UpdateTemplateRequest, opts ...RequestOption) (*UpdateTemplateResponse, error)
+	// DeleteTemplate deletes a template and its files.
+	DeleteTemplate(ctx context.Context, templateId string, opts ...RequestOption) (*DeleteTemplateResponse, error)
+	// ExtractTemplateFiles extracts the uploaded ZIP archive of a template.
+	ExtractTemplateFiles(ctx context.Context, templateId string, opts ...RequestOption) error
+	// GenerateTemplatePreviews renders PNG and PDF previews of a template.
+	GenerateTemplatePreviews(ctx context.Context, templateId string, request *GenerateTemplatePreviewsRequest, opts ...RequestOption) (*GenerateTemplatePreviewsResponse, error)
+	// GeneratePresignedGetUrl returns a presigned URL to download the files of a template from.
+	GeneratePresignedGetUrl(ctx context.Context, templateId string, opts ...RequestOption) (*GeneratePresignedGetUrlResponse, error)
+	// GetTemplateIndexHtml returns the index.html of a template.
+	GetTemplateIndexHtml(ctx context.Context, templateId string, opts ...RequestOption) (*GetTemplateIndexHtmlResponse, error)
+	// UploadTemplateIndexHtml replaces the index.html of a template.
+	UploadTemplateIndexHtml(ctx context.Context, templateId string, request *UploadTemplateIndexHtmlRequest, opts ...RequestOption) error
+	// CloneTemplate creates a copy of a template.
+	CloneTemplate(ctx context.Context, templateId string, opts ...RequestOption) (*CloneTemplateResponse, error)
+}
+
+// DocumentsAPI is the set of document endpoints of the Pogodoc API,
+// implemented by the generated documents client.
+// Substitute it with NewPogodocClient to test code using PogodocClient without the API.
+type DocumentsAPI interface {
+	// InitializeRenderJob creates a render job and returns presigned URLs to upload its data and template to.
+	InitializeRenderJob(ctx context.Context, request *InitializeRenderJobRequest, opts ...RequestOption) (*InitializeRenderJobResponse, error)
+	// StartRenderJob starts rendering a job created with InitializeRenderJob.
+	StartRenderJob(ctx context.Context, jobId string, request *StartRenderJobRequest, opts ...RequestOption) (*StartRenderJobResponse, error)
+	// StartImmediateRender renders a document in a single request.
+	StartImmediateRender(ctx context.Context, request *StartImmediateRenderRequest, opts ...RequestOption) (*StartImmediateRenderResponse, error)
+	// GetJobStatus returns the status of a render job.
+	GetJobStatus(ctx context.Context, jobId string, opts ...RequestOption) (*GetJobStatusResponse, error)
+}
+
+var (
+	_ TemplatesAPI = (*templates.Client)(nil)
+	_ DocumentsAPI = (*documents.Client)(nil)
+)
+
+// NewPogodocClient assembles a PogodocClient from implementations of the
+// template and document endpoints, such as the mocks of the pogodocmock package.
+// The request options configure the requests PogodocClient makes itself,
+// like uploads to and downloads from presigned URLs, and its logging, tracing and metrics.
+func NewPogodocClient(templatesAPI TemplatesAPI, documentsAPI DocumentsAPI, opts ...RequestOption) *PogodocClient {
+	c := newPogodocClient(opts...)
+	c.Templates = &templatesClient{client: c, api: templatesAPI}
+	c.Documents = &documentsClient{client: c, api: documentsAPI}
+	return c
+}
+
+// templates returns the template endpoints the methods of PogodocClient call.
+// A PogodocClient built as a struct literal around a generated client has no
+// Templates, so the templates of the generated client are used.
+func (c *PogodocClient) templates() TemplatesAPI {
+	if c.Templates == nil && c.Client != nil {
+		return &templatesClient{client: c, api: c.Client.Templates}
+	}
+	return c.Templates
+}
+
+// documents returns the document endpoints the methods of PogodocClient call.
+// A PogodocClient built as a struct literal around a generated client has no
+// Documents, so the documents of the generated client are used.
+func (c *PogodocClient) documents() DocumentsAPI {
+	if c.Documents == nil && c.Client != nil {
+		return &documentsClient{client: c, api: c.Client.Documents}
+	}
+	return c.Documents
+}
+
+// callOptions prepares a call to the given endpoint through Templates or Documents.
+// The options of PogodocClient, and the attempts and HTTP client options the
+// generated client would otherwise handle itself, are passed to the transport
+// with the returned context. The other options are returned for the generated client.
+func (c *PogodocClient) callOptions(ctx context.Context, endpoint string, opts []RequestOption) (context.Context, []RequestOption) {
+	sdkOpts, clientOpts := splitOptions(opts)
+	config := c.config().with(sdkOpts)
+	var forwarded []RequestOption
+	for _, opt := range clientOpts {
+		switch opt := opt.(type) {
+		case *core.MaxAttemptsOption:
+			config.maxAttempts = opt.MaxAttempts
+		case *core.HTTPClientOption:
+			forwarded = append(forwarded, option.WithHTTPClient(&transport{client: opt.HTTPClient, config: config}))
+		default:
+			forwarded = append(forwarded, opt)
+		}
+	}
+	return withCall(ctx, &call{endpoint: endpoint, config: config}), forwarded
+}
+
+// templatesClient calls the template endpoints of a TemplatesAPI with the
+// options of PogodocClient, naming each call after its endpoint.
+type templatesClient struct {
+	client *PogodocClient
+	api    TemplatesAPI
+}
+
+func (t *templatesClient) InitializeTemplateCreation(ctx context.Context, opts ...RequestOption) (*InitializeTemplateCreationResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.InitializeTemplateCreation", opts)
+	response, err := t.api.InitializeTemplateCreation(ctx, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) SaveCreatedTemplate(ctx context.Context, templateId string, request *SaveCreatedTemplateRequest, opts ...RequestOption) error {
+	ctx, opts = t.client.callOptions(ctx, "templates.SaveCreatedTemplate", opts)
+	return fromCoreError(t.api.SaveCreatedTemplate(ctx, templateId, request, opts...))
+}
+
+func (t *templatesClient) UpdateTemplate(ctx context.Context, templateId string, request *UpdateTemplateRequest, opts ...RequestOption) (*UpdateTemplateResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.UpdateTemplate", opts)
+	response, err := t.api.UpdateTemplate(ctx, templateId, request, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) DeleteTemplate(ctx context.Context, templateId string, opts ...RequestOption) (*DeleteTemplateResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.DeleteTemplate", opts)
+	response, err := t.api.DeleteTemplate(ctx, templateId, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) ExtractTemplateFiles(ctx context.Context, templateId string, opts ...RequestOption) error {
+	ctx, opts = t.client.callOptions(ctx, "templates.ExtractTemplateFiles", opts)
+	return fromCoreError(t.api.ExtractTemplateFiles(ctx, templateId, opts...))
+}
+
+func (t *templatesClient) GenerateTemplatePreviews(ctx context.Context, templateId string, request *GenerateTemplatePreviewsRequest, opts ...RequestOption) (*GenerateTemplatePreviewsResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.GenerateTemplatePreviews", opts)
+	response, err := t.api.GenerateTemplatePreviews(ctx, templateId, request, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) GeneratePresignedGetUrl(ctx context.Context, templateId string, opts ...RequestOption) (*GeneratePresignedGetUrlResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.GeneratePresignedGetUrl", opts)
+	response, err := t.api.GeneratePresignedGetUrl(ctx, templateId, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) GetTemplateIndexHtml(ctx context.Context, templateId string, opts ...RequestOption) (*GetTemplateIndexHtmlResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.GetTemplateIndexHtml", opts)
+	response, err := t.api.GetTemplateIndexHtml(ctx, templateId, opts...)
+	return response, fromCoreError(err)
+}
+
+func (t *templatesClient) UploadTemplateIndexHtml(ctx context.Context, templateId string, request *UploadTemplateIndexHtmlRequest, opts ...RequestOption) error {
+	ctx, opts = t.client.callOptions(ctx, "templates.UploadTemplateIndexHtml", opts)
+	return fromCoreError(t.api.UploadTemplateIndexHtml(ctx, templateId, request, opts...))
+}
+
+func (t *templatesClient) CloneTemplate(ctx context.Context, templateId string, opts ...RequestOption) (*CloneTemplateResponse, error) {
+	ctx, opts = t.client.callOptions(ctx, "templates.CloneTemplate", opts)
+	response, err := t.api.CloneTemplate(ctx, templateId, opts...)
+	return response, fromCoreError(err)
+}
+
+// documentsClient calls the document endpoints of a DocumentsAPI with the
+// options of PogodocClient, naming each call after its endpoint.
+type documentsClient struct {
+	client *PogodocClient
+	api    DocumentsAPI
+}
+
+func (d *documentsClient) InitializeRenderJob(ctx context.Context, request *InitializeRenderJobRequest, opts ...RequestOption) (*InitializeRenderJobResponse, error) {
+	ctx, opts = d.client.callOptions(ctx, "documents.InitializeRenderJob", opts)
+	response, err := d.api.InitializeRenderJob(ctx, request, opts...)
+	return response, fromCoreError(err)
+}
+
+func (d *documentsClient) StartRenderJob(ctx context.Context, jobId string, request *StartRenderJobRequest, opts ...RequestOption) (*StartRenderJobResponse, error) {
+	ctx, opts = d.client.callOptions(ctx, "documents.StartRenderJob", opts)
+	response, err := d.api.StartRenderJob(ctx, jobId, request, opts...)
+	return response, fromCoreError(err)
+}
+
+func (d *documentsClient) StartImmediateRender(ctx context.Context, request *StartImmediateRenderRequest, opts ...RequestOption) (*StartImmediateRenderResponse, error) {
+	ctx, opts = d.client.callOptions(ctx, "documents.StartImmediateRender", opts)
+	response, err := d.api.StartImmediateRender(ctx, request, opts...)
+	return response, fromCoreError(err)
+}
+
+func (d *documentsClient) GetJobStatus(ctx context.Context, jobId string, opts ...RequestOption) (*GetJobStatusResponse, error) {
+	ctx, opts = d.client.callOptions(ctx, "documents.GetJobStatus", opts)
+	response, err := d.api.GetJobStatus(ctx, jobId, opts...)
+	return response, fromCoreError(err)
+}
@@ -0,0 +1,284 @@
+package pogodoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Pogodoc/pogodoc-go/client/core"
+)
+
+// requestIdHeaders are the response headers carrying the ID of a request,
+// used when the error body does not include one.
+var requestIdHeaders = []string{"X-Request-Id", "X-Amz-Request-Id"}
+
+// APIError is returned for error responses of the Pogodoc API. It matches
+// ErrUnauthorized, ErrNotFound, ErrRateLimited and ErrValidation according
+// to its status code.
+//
+// When the response body holds the JSON error envelope of the Pogodoc API,
+// its message, code, validation issues and request ID are decoded into the
+// corresponding fields. The raw body is always kept in Body.
+//
+// An APIError wraps the *core.APIError of the generated client, so code
+// reading the status code with errors.As and a *core.APIError keeps working.
+type APIError struct {
+	StatusCode int
+	// Message is the human-readable message of the error envelope.
+	Message string
+	// Code is the machine-readable error code of the error envelope.
+	Code string
+	// Issues lists the fields rejected by request validation.
+	Issues []ValidationIssue
+	// RequestId identifies the failed request, for support requests.
+	RequestId string
+	// Header holds the headers of the error response.
+	Header http.Header
+	// Body is the raw body of the error response.
+	Body []byte
+
+	// coreError is the error returned by an implementation of TemplatesAPI or
+	// DocumentsAPI, if the APIError was converted from it.
+	coreError *core.APIError
+}
+
+// ValidationIssue describes a field rejected by request validation.
+type ValidationIssue struct {
+	// Path locates the field in the request, e.g. "data.customer.name".
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// UnmarshalJSON accepts paths given as a string or as an array of segments,
+// and codes given as a string or a number.
+func (v *ValidationIssue) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Path    json.RawMessage `json:"path"`
+		Field   string          `json:"field"`
+		Message string          `json:"message"`
+		Code    json.RawMessage `json:"code"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*v = ValidationIssue{
+		Path:    jsonPath(raw.Path),
+		Message: raw.Message,
+		Code:    jsonString(raw.Code),
+	}
+	if v.Path == "" {
+		v.Path = raw.Field
+	}
+	return nil
+}
+
+// errorEnvelope is the JSON error body returned by the Pogodoc API.
+// The envelope may also be nested under "error".
+type errorEnvelope struct {
+	Message        string          `json:"message"`
+	Error          json.RawMessage `json:"error"`
+	Code           json.RawMessage `json:"code"`
+	Issues         json.RawMessage `json:"issues"`
+	Errors         json.RawMessage `json:"errors"`
+	RequestId      string          `json:"requestId"`
+	SnakeRequestId string          `json:"request_id"`
+}
+
+// newAPIError builds an *APIError from an error response, decoding its body
+// if it holds a JSON error envelope.
+func newAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiError := &APIError{StatusCode: statusCode, Header: header}
+	if len(body) > 0 {
+		apiError.Body = body
+		apiError.decodeEnvelope(body)
+	}
+	if apiError.RequestId == "" {
+		for _, name := range requestIdHeaders {
+			if requestId := header.Get(name); requestId != "" {
+				apiError.RequestId = requestId
+				break
+			}
+		}
+	}
+	return apiError
+}
+
+// fromCoreError converts the *core.APIError of an implementation of TemplatesAPI
+// or DocumentsAPI, such as a mock, into an *APIError. Other errors are returned as-is.
+func fromCoreError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	var coreErr *core.APIError
+	if !errors.As(err, &coreErr) {
+		return err
+	}
+	var body []byte
+	if cause := coreErr.Unwrap(); cause != nil {
+		body = []byte(cause.Error())
+	}
+	apiError := newAPIError(coreErr.StatusCode, nil, body)
+	apiError.coreError = coreErr
+	return apiError
+}
+
+// decodeEnvelope fills the fields of the error from a JSON error envelope.
+// Bodies that are not an envelope are ignored.
+func (e *APIError) decodeEnvelope(body []byte) {
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+	if len(envelope.Error) > 0 && envelope.Error[0] == '{' {
+		e.decodeEnvelope(envelope.Error)
+	} else if e.Message == "" {
+		e.Message = jsonString(envelope.Error)
+	}
+	if envelope.Message != "" {
+		e.Message = envelope.Message
+	}
+	if code := jsonString(envelope.Code); code != "" {
+		e.Code = code
+	}
+	if issues := decodeIssues(envelope.Issues); len(issues) > 0 {
+		e.Issues = issues
+	} else if issues := decodeIssues(envelope.Errors); len(issues) > 0 {
+		e.Issues = issues
+	}
+	if envelope.RequestId != "" {
+		e.RequestId = envelope.RequestId
+	} else if envelope.SnakeRequestId != "" {
+		e.RequestId = envelope.SnakeRequestId
+	}
+}
+
+// decodeIssues decodes a list of validation issues, given either as objects
+// or as plain messages.
+func decodeIssues(raw json.RawMessage) []ValidationIssue {
+	if len(raw) == 0 {
+		return nil
+	}
+	var issues []ValidationIssue
+	if err := json.Unmarshal(raw, &issues); err == nil {
+		return issues
+	}
+	var messages []string
+	if err := json.Unmarshal(raw, &messages); err != nil {
+		return nil
+	}
+	issues = make([]ValidationIssue, len(messages))
+	for i, message := range messages {
+		issues[i] = ValidationIssue{Message: message}
+	}
+	return issues
+}
+
+// jsonPath returns a path given as a string or as an array of segments,
+// joined with dots.
+func jsonPath(raw json.RawMessage) string {
+	if path := jsonString(raw); path != "" || len(raw) == 0 || raw[0] != '[' {
+		return path
+	}
+	var segments []any
+	if err := json.Unmarshal(raw, &segments); err != nil {
+		return ""
+	}
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		parts[i] = fmt.Sprint(segment)
+	}
+	return strings.Join(parts, ".")
+}
+
+// jsonString returns a JSON string, or the literal of any other scalar value.
+func jsonString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if raw[0] == '{' || raw[0] == '[' {
+		return ""
+	}
+	return string(raw)
+}
+
+// Is reports whether the API error matches one of the sentinel errors
+// of this package, based on its status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// Unwrap returns the error as the *core.APIError of the generated client,
+// holding the status code and the raw body.
+func (e *APIError) Unwrap() error {
+	if e.coreError != nil {
+		return e.coreError
+	}
+	var cause error
+	if len(e.Body) > 0 {
+		cause = errors.New(string(e.Body))
+	}
+	return core.NewAPIError(e.StatusCode, cause)
+}
+
+// Error returns the status code and the message of the error. The message
+// of a decoded error envelope is preferred to the raw body.
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.envelopeError()
+	}
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("%d", e.StatusCode)
+	}
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Body)
+}
+
+// envelopeError formats a decoded error envelope, e.g.
+// `422 validation_error: Invalid data (data.name: Required) [request ID: abc]`.
+func (e *APIError) envelopeError() string {
+	var b strings.Builder
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, "%d", e.StatusCode)
+	}
+	if e.Code != "" {
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(e.Code)
+	}
+	if b.Len() > 0 {
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	if len(e.Issues) > 0 {
+		issues := make([]string, len(e.Issues))
+		for i, issue := range e.Issues {
+			issues[i] = issue.Message
+			if issue.Path != "" {
+				issues[i] = issue.Path + ": " + issue.Message
+			}
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(issues, "; "))
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&b, " [request ID: %s]", e.RequestId)
+	}
+	return b.String()
+}
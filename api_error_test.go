@@ -0,0 +1,158 @@
+package pogodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Pogodoc/pogodoc-go/client/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		giveStatusCode int
+		wantError      error
+	}{
+		{giveStatusCode: http.StatusBadRequest, wantError: ErrValidation},
+		{giveStatusCode: http.StatusUnauthorized, wantError: ErrUnauthorized},
+		{giveStatusCode: http.StatusForbidden, wantError: ErrUnauthorized},
+		{giveStatusCode: http.StatusNotFound, wantError: ErrNotFound},
+		{giveStatusCode: http.StatusUnprocessableEntity, wantError: ErrValidation},
+		{giveStatusCode: http.StatusTooManyRequests, wantError: ErrRateLimited},
+		{giveStatusCode: http.StatusInternalServerError},
+	}
+
+	sentinels := []error{ErrUnauthorized, ErrNotFound, ErrRateLimited, ErrValidation}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.giveStatusCode), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", newAPIError(tt.giveStatusCode, nil, nil))
+			for _, sentinel := range sentinels {
+				assert.Equal(t, sentinel == tt.wantError, errors.Is(err, sentinel), sentinel.Error())
+			}
+			var apiError *APIError
+			assert.ErrorAs(t, err, &apiError)
+			assert.Equal(t, tt.giveStatusCode, apiError.StatusCode)
+		})
+	}
+}
+
+func TestDecodeErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		description    string
+		giveStatusCode int
+		giveHeader     http.Header
+		giveBody       string
+		wantMessage    string
+		wantCode       string
+		wantIssues     []ValidationIssue
+		wantRequestId  string
+		wantError      string
+	}{
+		{
+			description:    "validation issues",
+			giveStatusCode: http.StatusUnprocessableEntity,
+			giveBody:       `{"message":"Invalid request","code":"validation_error","issues":[{"path":["data","items",0,"name"],"message":"Required","code":"invalid_type"}],"requestId":"req_1"}`,
+			wantMessage:    "Invalid request",
+			wantCode:       "validation_error",
+			wantIssues:     []ValidationIssue{{Path: "data.items.0.name", Message: "Required", Code: "invalid_type"}},
+			wantRequestId:  "req_1",
+			wantError:      "422 validation_error: Invalid request (data.items.0.name: Required) [request ID: req_1]",
+		},
+		{
+			description:    "nested envelope and request ID header",
+			giveStatusCode: http.StatusNotFound,
+			giveHeader:     http.Header{"X-Request-Id": []string{"req_2"}},
+			giveBody:       `{"error":{"message":"Template not found","code":404}}`,
+			wantMessage:    "Template not found",
+			wantCode:       "404",
+			wantRequestId:  "req_2",
+			wantError:      "404 404: Template not found [request ID: req_2]",
+		},
+		{
+			description:    "error string and plain issues",
+			giveStatusCode: http.StatusBadRequest,
+			giveBody:       `{"error":"Bad Request","errors":["type is required"],"request_id":"req_3"}`,
+			wantMessage:    "Bad Request",
+			wantIssues:     []ValidationIssue{{Message: "type is required"}},
+			wantRequestId:  "req_3",
+			wantError:      "400: Bad Request (type is required) [request ID: req_3]",
+		},
+		{
+			description:    "not an envelope",
+			giveStatusCode: http.StatusBadGateway,
+			giveBody:       `<html>Bad Gateway</html>`,
+			wantError:      "502: <html>Bad Gateway</html>",
+		},
+		{
+			description:    "empty body",
+			giveStatusCode: http.StatusServiceUnavailable,
+			wantError:      "503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				for name, values := range tt.giveHeader {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(tt.giveStatusCode)
+				_, _ = w.Write([]byte(tt.giveBody))
+			}))
+			defer server.Close()
+
+			client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithMaxAttempts(1))
+			require.NoError(t, err)
+			_, err = client.Templates.DeleteTemplate(context.Background(), "template")
+
+			var apiError *APIError
+			require.ErrorAs(t, err, &apiError)
+			assert.Equal(t, tt.giveStatusCode, apiError.StatusCode)
+			assert.Equal(t, tt.wantMessage, apiError.Message)
+			assert.Equal(t, tt.wantCode, apiError.Code)
+			assert.Equal(t, tt.wantIssues, apiError.Issues)
+			assert.Equal(t, tt.wantRequestId, apiError.RequestId)
+			assert.Equal(t, "application/json", apiError.Header.Get("Content-Type"))
+			assert.Equal(t, tt.giveBody, string(apiError.Body))
+			assert.EqualError(t, err, tt.wantError)
+		})
+	}
+}
+
+func TestAPIErrorUnwrapsCoreError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := PogodocClientInitWithConfig(server.URL, "test-token")
+	require.NoError(t, err)
+
+	calls := map[string]func() error{
+		"wrapper": func() error {
+			_, err := client.Documents.GetJobStatus(context.Background(), "job")
+			return err
+		},
+		"generated client": func() error {
+			_, err := client.Client.Documents.GetJobStatus(context.Background(), "job")
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			var coreErr *core.APIError
+			require.ErrorAs(t, err, &coreErr)
+			assert.Equal(t, http.StatusNotFound, coreErr.StatusCode)
+			assert.EqualError(t, coreErr, "404: Job not found\n")
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package pogodoc
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TemplateIgnoreFile is the name of the file, at the root of a template directory,
+// listing the paths to leave out of the template archive.
+const TemplateIgnoreFile = ".pogodocignore"
+
+// templateIndexFile is the file every template archive must contain.
+const templateIndexFile = "index.html"
+
+// archiveModTime is the modification time written for every archive entry, so that
+// archiving the same directory twice produces identical bytes.
+var archiveModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// defaultIgnorePatterns are always left out of template archives.
+var defaultIgnorePatterns = []string{TemplateIgnoreFile, ".git/", ".DS_Store"}
+
+// TemplateOption adapts how a template is packaged and uploaded.
+// It is accepted by SaveTemplate, UpdateTemplate and their FromFileStream and FromDirectory variants.
+type TemplateOption func(*templateOptions)
+
+// WithEntryFile requires the given file, relative to the template directory
+// (e.g. "src/index.jsx"), to be present in the archive in addition to index.html.
+func WithEntryFile(entryFile string) TemplateOption {
+	return func(opts *templateOptions) {
+		opts.entryFile = entryFile
+	}
+}
+
+// WithUploadProgress reports the progress of the template archive upload.
+func WithUploadProgress(progress ProgressFunc) TemplateOption {
+	return func(opts *templateOptions) {
+		opts.progress = progress
+	}
+}
+
+type templateOptions struct {
+	entryFile string
+	progress  ProgressFunc
+}
+
+func newTemplateOptions(opts []TemplateOption) *templateOptions {
+	options := new(templateOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// ArchiveTemplateDirectory writes a ZIP archive of dir to w in the format expected by ExtractTemplateFiles.
+// Paths are stored relative to dir with forward slashes, in lexical order and with fixed timestamps
+// and permissions, so the archive is reproducible. Paths matching the patterns in dir/.pogodocignore are skipped.
+// It fails before writing anything if index.html or the configured entry file is missing.
+func ArchiveTemplateDirectory(dir string, w io.Writer, opts ...TemplateOption) error {
+	options := newTemplateOptions(opts)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("error: %s is not a directory", dir)
+	}
+
+	ignore, err := loadIgnorePatterns(filepath.Join(dir, TemplateIgnoreFile))
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if filePath == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.matches(rel, entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading template directory: %w", err)
+	}
+
+	required := []string{templateIndexFile}
+	if options.entryFile != "" {
+		required = append(required, path.Clean(filepath.ToSlash(options.entryFile)))
+	}
+	for _, name := range required {
+		if !slices.Contains(files, name) {
+			return fmt.Errorf("error: template is missing %s", name)
+		}
+	}
+
+	archive := zip.NewWriter(w)
+	for _, name := range files {
+		if err := addArchiveFile(archive, filepath.Join(dir, filepath.FromSlash(name)), name); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// SaveTemplateFromDirectory packages a template directory with ArchiveTemplateDirectory
+// and saves it like SaveTemplate. The archive is staged in a temporary file, which is removed afterwards.
+func (c *PogodocClient) SaveTemplateFromDirectory(dir string, metadata SaveCreatedTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (string, error) {
+	fsProps, cleanup, err := archiveToTempFile(dir, opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	return c.SaveTemplateFromFileStream(fsProps, metadata, ctx, opts...)
+}
+
+// UpdateTemplateFromDirectory packages a template directory with ArchiveTemplateDirectory
+// and updates an existing template like UpdateTemplate. The archive is staged in a temporary file,
+// which is removed afterwards.
+func (c *PogodocClient) UpdateTemplateFromDirectory(templateId string, dir string, metadata UpdateTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (string, error) {
+	fsProps, cleanup, err := archiveToTempFile(dir, opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	return c.UpdateTemplateFromFileStream(templateId, fsProps, metadata, ctx, opts...)
+}
+
+// archiveToTempFile archives dir into a temporary file and returns a stream over it,
+// along with a function that removes the file.
+func archiveToTempFile(dir string, opts []TemplateOption) (FileStreamProps, func(), error) {
+	file, err := os.CreateTemp("", "pogodoc-template-*.zip")
+	if err != nil {
+		return FileStreamProps{}, nil, fmt.Errorf("creating archive: %w", err)
+	}
+	cleanup := func() {
+		os.Remove(file.Name())
+	}
+
+	buffered := bufio.NewWriter(file)
+	err = ArchiveTemplateDirectory(dir, buffered, opts...)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return FileStreamProps{}, nil, err
+	}
+
+	fsProps, err := FileStreamFromFile(file.Name())
+	if err != nil {
+		cleanup()
+		return FileStreamProps{}, nil, err
+	}
+	return fsProps, cleanup, nil
+}
+
+func addArchiveFile(archive *zip.Writer, filePath string, name string) error {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: archiveModTime,
+	}
+	header.SetMode(0o644)
+
+	writer, err := archive.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(writer, file); err != nil {
+		return fmt.Errorf("archiving %s: %w", name, err)
+	}
+	return nil
+}
+
+// ignorePattern is a single line of a .pogodocignore file.
+type ignorePattern struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignorePatterns implements the subset of the .gitignore syntax supported in
+// .pogodocignore files: comments, negation with "!", directory-only patterns
+// with a trailing "/", and patterns anchored to the template root when they
+// contain a "/". Patterns are matched with path.Match.
+type ignorePatterns []ignorePattern
+
+func loadIgnorePatterns(ignoreFile string) (ignorePatterns, error) {
+	lines := append([]string(nil), defaultIgnorePatterns...)
+
+	file, err := os.Open(ignoreFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", TemplateIgnoreFile, err)
+		}
+	}
+
+	var patterns ignorePatterns
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", TemplateIgnoreFile, line, err)
+		}
+		p.pattern = line
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// matches reports whether the slash-separated path rel should be ignored.
+// The last matching pattern wins, so negated patterns can re-include paths.
+func (patterns ignorePatterns) matches(rel string, isDir bool) bool {
+	ignored := false
+	for _, p := range patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		name := path.Base(rel)
+		if p.anchored {
+			name = rel
+		}
+		if ok, _ := path.Match(p.pattern, name); ok {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
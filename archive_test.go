@@ -0,0 +1,85 @@
+package pogodoc
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplateDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func archiveEntries(t *testing.T, archive []byte) []string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	return names
+}
+
+func TestArchiveTemplateDirectory(t *testing.T) {
+	files := map[string]string{
+		"index.html":              "<html></html>",
+		"src/index.jsx":           "export default () => null",
+		"src/styles/main.css":     "body {}",
+		"src/debug.log":           "noise",
+		"node_modules/react/x.js": "vendored",
+		"assets/logo.png":         "png",
+		"assets/keep.log":         "kept",
+		".git/HEAD":               "ref",
+		TemplateIgnoreFile:        "# build output\nnode_modules/\n*.log\n!assets/keep.log\n",
+	}
+
+	t.Run("honors .pogodocignore and orders entries", func(t *testing.T) {
+		dir := writeTemplateDir(t, files)
+
+		var buf bytes.Buffer
+		require.NoError(t, ArchiveTemplateDirectory(dir, &buf, WithEntryFile("src/index.jsx")))
+		assert.Equal(t, []string{
+			"assets/keep.log",
+			"assets/logo.png",
+			"index.html",
+			"src/index.jsx",
+			"src/styles/main.css",
+		}, archiveEntries(t, buf.Bytes()))
+	})
+
+	t.Run("produces reproducible archives", func(t *testing.T) {
+		var first, second bytes.Buffer
+		require.NoError(t, ArchiveTemplateDirectory(writeTemplateDir(t, files), &first))
+		require.NoError(t, ArchiveTemplateDirectory(writeTemplateDir(t, files), &second))
+		assert.Equal(t, first.Bytes(), second.Bytes())
+	})
+
+	t.Run("fails early without index.html", func(t *testing.T) {
+		dir := writeTemplateDir(t, map[string]string{"main.html": "<html></html>"})
+
+		var buf bytes.Buffer
+		require.ErrorContains(t, ArchiveTemplateDirectory(dir, &buf), "index.html")
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("fails early without the entry file", func(t *testing.T) {
+		dir := writeTemplateDir(t, map[string]string{"index.html": "<html></html>"})
+
+		var buf bytes.Buffer
+		require.ErrorContains(t, ArchiveTemplateDirectory(dir, &buf, WithEntryFile("./src/index.jsx")), "src/index.jsx")
+		assert.Zero(t, buf.Len())
+	})
+}
@@ -0,0 +1,231 @@
+package pogodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const defaultBatchConcurrency = 4
+
+// attrBatchSize is the span attribute recording the number of documents of a batch.
+const attrBatchSize = attribute.Key("pogodoc.batch.size")
+
+// ErrBatchAborted is the error of the documents of a batch that were not
+// generated because an earlier document failed with BatchOptions.FailFast.
+var ErrBatchAborted = errors.New("pogodoc: batch aborted after a document failed")
+
+// BatchOptions configure GenerateDocumentsBatch and GenerateDocumentsBatchStream.
+type BatchOptions struct {
+	// Concurrency is the number of documents generated at the same time.
+	// Zero or negative values use the default of 4.
+	Concurrency int
+	// RateLimit is the maximum number of documents started per second.
+	// Zero means no limit.
+	RateLimit float64
+	// FailFast stops the batch at the first document that fails: the documents
+	// being generated are canceled and the remaining ones fail with ErrBatchAborted.
+	// By default, the batch continues on errors.
+	FailFast bool
+	// PollOptions configure the polling of every render job.
+	PollOptions PollOptions
+}
+
+// BatchResult is the result of a document of a batch.
+type BatchResult struct {
+	// Index is the index of the document in the batch.
+	Index int
+	// JobStatus is the final status of the render job, if it completed.
+	JobStatus *GetJobStatusResponse
+	// Err is the error generating the document, if any.
+	Err error
+}
+
+// BatchError is returned by GenerateDocumentsBatch when documents of the batch failed.
+// It matches the errors of the failed documents with errors.Is and errors.As.
+type BatchError struct {
+	// Total is the number of documents of the batch.
+	Total int
+	// Errors are the errors of the failed documents, in input order.
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d documents failed, first error: %v", len(e.Errors), e.Total, e.Errors[0])
+}
+
+// Unwrap returns the errors of the failed documents.
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// GenerateDocumentsBatch generates a document for each of the given props, like GenerateDocument,
+// with a pool of BatchOptions.Concurrency workers.
+// It returns the results in input order, and a *BatchError if any document failed.
+func (c *PogodocClient) GenerateDocumentsBatch(gdProps []GenerateDocumentProps, opts BatchOptions, ctx context.Context) (_ []BatchResult, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDocumentsBatch", attrBatchSize.Int(len(gdProps)))
+	defer func() { endSpan(span, err) }()
+
+	return collectBatch(ctx, c.GenerateDocumentsBatchStream(gdProps, opts, ctx), len(gdProps))
+}
+
+// GenerateDocumentsBatchStream is like GenerateDocumentsBatch, but sends the result
+// of every document on the returned channel as soon as it is done, in completion order.
+// The channel is closed once every document has a result, or once ctx is canceled:
+// the results not received by then are dropped. It must be drained, unless ctx is canceled.
+func (c *PogodocClient) GenerateDocumentsBatchStream(gdProps []GenerateDocumentProps, opts BatchOptions, ctx context.Context) <-chan BatchResult {
+	return c.runBatch(ctx, len(gdProps), opts, func(ctx context.Context, index int) (*GetJobStatusResponse, error) {
+		return c.GenerateDocument(gdProps[index], ctx, opts.PollOptions)
+	})
+}
+
+// collectBatch gathers the results of a batch of the given size in input order.
+// The documents whose results were dropped because ctx was canceled fail with its error.
+func collectBatch(ctx context.Context, results <-chan BatchResult, total int) ([]BatchResult, error) {
+	ordered := make([]BatchResult, total)
+	received := make([]bool, total)
+	for result := range results {
+		ordered[result.Index] = result
+		received[result.Index] = true
+	}
+	for i := range ordered {
+		if !received[i] {
+			ordered[i] = BatchResult{Index: i, Err: ctx.Err()}
+		}
+	}
+
+	var errs []error
+	for _, result := range ordered {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	if len(errs) > 0 {
+		return ordered, &BatchError{Total: total, Errors: errs}
+	}
+	return ordered, nil
+}
+
+// runBatch generates count documents with generate on a pool of workers,
+// and sends their results on the returned channel.
+func (c *PogodocClient) runBatch(
+	ctx context.Context,
+	count int,
+	opts BatchOptions,
+	generate func(ctx context.Context, index int) (*GetJobStatusResponse, error),
+) <-chan BatchResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	concurrency = min(concurrency, max(count, 1))
+
+	results := make(chan BatchResult, concurrency)
+	indexes := make(chan int)
+	batchCtx, cancel := context.WithCancel(ctx)
+	limiter := newRateLimiter(opts.RateLimit)
+
+	var failed sync.Once
+	var workers sync.WaitGroup
+	for range concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for index := range indexes {
+				jobStatus, err := generate(batchCtx, index)
+				if err != nil && opts.FailFast {
+					first := false
+					failed.Do(func() {
+						first = true
+						c.logger().WarnContext(ctx, "aborting batch after a document failed", slog.Int("index", index), slog.Any("error", err))
+						cancel()
+					})
+					// Documents canceled by the abort report it rather than the cancellation.
+					if !first && ctx.Err() == nil && errors.Is(err, context.Canceled) {
+						err = fmt.Errorf("%w: %w", ErrBatchAborted, err)
+					}
+				}
+				select {
+				case results <- BatchResult{Index: index, JobStatus: jobStatus, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		defer cancel()
+
+		next := 0
+	dispatch:
+		for ; next < count; next++ {
+			if limiter.wait(batchCtx) != nil {
+				break
+			}
+			select {
+			case indexes <- next:
+			case <-batchCtx.Done():
+				break dispatch
+			}
+		}
+		close(indexes)
+		workers.Wait()
+
+		for ; next < count; next++ {
+			err := ErrBatchAborted
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			select {
+			case results <- BatchResult{Index: next, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// rateLimiter spaces out events to a maximum rate per second.
+type rateLimiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter returns a limiter for the given rate per second.
+// A rate of zero or less does not limit.
+func newRateLimiter(rate float64) *rateLimiter {
+	if rate <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// wait blocks until the next event is allowed, or ctx is done.
+// It must not be called concurrently.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+	now := time.Now()
+	if delay := l.next.Sub(now); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		now = l.next
+	}
+	l.next = now.Add(l.interval)
+	return nil
+}
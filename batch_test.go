@@ -0,0 +1,195 @@
+package pogodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pogodoc/pogodoc-go/pogodoctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchProps returns the props of count documents rendering {"index": i}.
+func batchProps(count int) []GenerateDocumentProps {
+	gdProps := make([]GenerateDocumentProps, count)
+	for i := range gdProps {
+		gdProps[i] = GenerateDocumentProps{
+			InitializeRenderJobRequest: InitializeRenderJobRequest{
+				Type:   InitializeRenderJobRequestTypeHtml,
+				Target: InitializeRenderJobRequestTargetPdf,
+			},
+			Template: String("<h1>Invoice</h1>"),
+			Data:     map[string]interface{}{"index": i},
+		}
+	}
+	return gdProps
+}
+
+// failingRenderer fails the renders of the documents with the given indexes
+// and counts the renders running at the same time.
+func failingRenderer(inFlight, maxInFlight *atomic.Int32, failing ...int) pogodoctest.Renderer {
+	return func(request pogodoctest.RenderRequest) ([]byte, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		index := int(request.Data["index"].(float64))
+		for _, failing := range failing {
+			if index == failing {
+				return nil, fmt.Errorf("cannot render invoice %d", index)
+			}
+		}
+		return pogodoctest.DefaultRenderer(request)
+	}
+}
+
+func TestGenerateDocumentsBatch(t *testing.T) {
+	fastPoll := PollOptions{InitialDelay: -1, Interval: time.Millisecond}
+	ctx := context.Background()
+
+	t.Run("results are in input order with bounded concurrency", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := pogodoctest.NewServer(pogodoctest.WithRenderer(failingRenderer(&inFlight, &maxInFlight)))
+		defer server.Close()
+
+		results, err := newTestClient(t, server.URL).GenerateDocumentsBatch(batchProps(12), BatchOptions{
+			Concurrency: 3,
+			PollOptions: fastPoll,
+		}, ctx)
+		require.NoError(t, err)
+		require.Len(t, results, 12)
+		for i, result := range results {
+			assert.Equal(t, i, result.Index)
+			require.NoError(t, result.Err)
+			job, ok := server.Job(result.JobStatus.JobId)
+			require.True(t, ok)
+			assert.JSONEq(t, fmt.Sprintf(`{"index":%d}`, i), string(job.Data))
+		}
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	})
+
+	t.Run("continues on errors", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := pogodoctest.NewServer(pogodoctest.WithRenderer(failingRenderer(&inFlight, &maxInFlight, 1, 3)))
+		defer server.Close()
+
+		results, err := newTestClient(t, server.URL).GenerateDocumentsBatch(batchProps(5), BatchOptions{PollOptions: fastPoll}, ctx)
+		var batchErr *BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, 5, batchErr.Total)
+		assert.Len(t, batchErr.Errors, 2)
+		assert.ErrorIs(t, err, ErrJobFailed)
+
+		for i, result := range results {
+			if i == 1 || i == 3 {
+				var renderErr *RenderJobError
+				require.ErrorAs(t, result.Err, &renderErr)
+				assert.Equal(t, fmt.Sprintf("cannot render invoice %d", i), renderErr.Message)
+				continue
+			}
+			require.NoError(t, result.Err)
+			assert.Equal(t, "done", result.JobStatus.Status)
+		}
+	})
+
+	t.Run("fail fast aborts the remaining documents", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := pogodoctest.NewServer(pogodoctest.WithRenderer(failingRenderer(&inFlight, &maxInFlight, 1)))
+		defer server.Close()
+
+		results, err := newTestClient(t, server.URL).GenerateDocumentsBatch(batchProps(6), BatchOptions{
+			Concurrency: 1,
+			FailFast:    true,
+			PollOptions: fastPoll,
+		}, ctx)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrJobFailed)
+		assert.ErrorIs(t, err, ErrBatchAborted)
+
+		require.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, ErrJobFailed)
+		for _, result := range results[2:] {
+			assert.ErrorIs(t, result.Err, ErrBatchAborted)
+		}
+		assert.Equal(t, 2, server.Jobs())
+	})
+
+	t.Run("rate limit spaces out documents", func(t *testing.T) {
+		server := pogodoctest.NewServer()
+		defer server.Close()
+
+		start := time.Now()
+		_, err := newTestClient(t, server.URL).GenerateDocumentsBatch(batchProps(4), BatchOptions{
+			Concurrency: 4,
+			RateLimit:   50,
+			PollOptions: fastPoll,
+		}, ctx)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		server := pogodoctest.NewServer()
+		defer server.Close()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		results, err := newTestClient(t, server.URL).GenerateDocumentsBatch(batchProps(3), BatchOptions{PollOptions: fastPoll}, canceled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, errors.Is(err, ErrBatchAborted))
+		assert.Len(t, results, 3)
+		assert.Zero(t, server.Jobs())
+	})
+}
+
+func TestGenerateDocumentsBatchStream(t *testing.T) {
+	server := pogodoctest.NewServer()
+	defer server.Close()
+
+	seen := make(map[int]bool)
+	for result := range newTestClient(t, server.URL).GenerateDocumentsBatchStream(batchProps(5), BatchOptions{
+		Concurrency: 2,
+		PollOptions: PollOptions{InitialDelay: -1, Interval: time.Millisecond},
+	}, context.Background()) {
+		require.NoError(t, result.Err)
+		assert.False(t, seen[result.Index], "duplicate result for document %d", result.Index)
+		seen[result.Index] = true
+	}
+	assert.Len(t, seen, 5)
+}
+
+func TestRunBatchStopsSendingWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	goroutines := runtime.NumGoroutine()
+
+	// The consumer cancels while the first result is unread, and stops reading.
+	results := (&PogodocClient{}).runBatch(ctx, 5, BatchOptions{Concurrency: 1}, func(_ context.Context, index int) (*GetJobStatusResponse, error) {
+		if index == 1 {
+			cancel()
+		}
+		return nil, nil
+	})
+
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > goroutines; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the batch is still sending results after the context was canceled")
+		}
+	}
+	var received []int
+	for result := range results {
+		received = append(received, result.Index)
+	}
+	assert.Equal(t, []int{0}, received)
+}
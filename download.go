@@ -0,0 +1,211 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrIncompleteDownload is returned when a download ends before the number of
+// bytes announced in the Content-Length header has been received.
+var ErrIncompleteDownload = errors.New("pogodoc: incomplete download")
+
+// DownloadOption adapts how rendered output is downloaded.
+type DownloadOption func(*downloadOptions)
+
+// WithDownloadProgress reports the progress of the download. The total is taken
+// from the Content-Length header, and is -1 if the server did not send one.
+func WithDownloadProgress(progress ProgressFunc) DownloadOption {
+	return func(opts *downloadOptions) {
+		opts.progress = progress
+	}
+}
+
+type downloadOptions struct {
+	progress ProgressFunc
+}
+
+// maxDownloadAttempts bounds how many times a download is restarted after
+// its body copy failed partway.
+const maxDownloadAttempts = 3
+
+// DownloadResult streams the rendered output at url into w without buffering it in memory.
+// The url is typically the Output.Data.Url of a finished job or the Url of a StartImmediateRenderResponse.
+// The request uses the HTTPClient configured on the client and is retried on transient failures.
+// If the body copy fails partway, the download is restarted when w can be rewound: an *os.File,
+// a *bytes.Buffer or any writer with Seek and Truncate methods. Otherwise the error is returned.
+func (c *PogodocClient) DownloadResult(url string, w io.Writer, ctx context.Context, opts ...DownloadOption) (err error) {
+	ctx, span := c.startSpan(ctx, "DownloadResult")
+	defer func() { endSpan(span, err) }()
+
+	options := new(downloadOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	rewind := rewinder(w)
+	for attempt := 1; ; attempt++ {
+		copyFailed, err := c.download(ctx, url, w, options)
+		if err == nil || !copyFailed || rewind == nil || attempt == maxDownloadAttempts || ctx.Err() != nil {
+			return err
+		}
+		c.logger().WarnContext(ctx, "restarting download", slog.Int("attempt", attempt+1), slog.Any("error", err))
+		if err := rewind(); err != nil {
+			return &StepError{Step: StepDownloadResult, Err: fmt.Errorf("rewinding output: %w", err)}
+		}
+	}
+}
+
+// download makes one attempt of DownloadResult. It reports whether the error,
+// if any, happened while copying the body, after w may have been written to.
+func (c *PogodocClient) download(ctx context.Context, url string, w io.Writer, options *downloadOptions) (copyFailed bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.transfer(req, "download")
+	if err != nil {
+		return false, &StepError{Step: StepDownloadResult, Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, &StepError{Step: StepDownloadResult, Err: newTransferError(resp)}
+	}
+
+	if options.progress != nil {
+		w = &progressWriter{Writer: w, total: resp.ContentLength, progress: options.progress}
+	}
+	written, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return true, &StepError{Step: StepDownloadResult, Err: err}
+	}
+	if resp.ContentLength >= 0 && written != resp.ContentLength {
+		return true, fmt.Errorf("%w: received %d of %d bytes", ErrIncompleteDownload, written, resp.ContentLength)
+	}
+	c.logger().DebugContext(ctx, "downloaded result", slog.Int64("bytes", written))
+	return false, nil
+}
+
+// rewinder returns a function discarding everything written to w after the call,
+// or nil if w cannot be rewound.
+func rewinder(w io.Writer) func() error {
+	switch w := w.(type) {
+	case interface {
+		io.Seeker
+		Truncate(size int64) error
+	}:
+		start, err := w.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil
+		}
+		return func() error {
+			if err := w.Truncate(start); err != nil {
+				return err
+			}
+			_, err := w.Seek(start, io.SeekStart)
+			return err
+		}
+	case *bytes.Buffer:
+		start := w.Len()
+		return func() error {
+			w.Truncate(start)
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+// GenerateDocumentToWriter generates a document like GenerateDocument and streams the rendered output into w.
+// It returns the final job status.
+func (c *PogodocClient) GenerateDocumentToWriter(gdProps GenerateDocumentProps, w io.Writer, ctx context.Context, opts ...PollOptions) (_ *GetJobStatusResponse, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDocumentToWriter", renderRequestAttributes(gdProps)...)
+	defer func() { endSpan(span, err) }()
+
+	// Fail before starting a render job that could not be polled.
+	if _, err := resolvePollOptions(opts); err != nil {
+		return nil, err
+	}
+
+	job, err := c.StartGenerateDocumentJob(gdProps, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting document generation: %w", err)
+	}
+	jobStatus, err := job.Wait(ctx, opts...)
+	if err != nil {
+		return jobStatus, err
+	}
+	var downloadOpts []DownloadOption
+	if gdProps.DownloadProgress != nil {
+		downloadOpts = append(downloadOpts, WithDownloadProgress(gdProps.DownloadProgress))
+	}
+	if err := job.Download(ctx, w, downloadOpts...); err != nil {
+		return jobStatus, err
+	}
+	return jobStatus, nil
+}
+
+// GenerateDocumentToFile generates a document like GenerateDocument and writes the rendered output to path.
+// The file is written to a temporary file in the same directory first and only renamed into place
+// once the download completed, so path never holds a partial document.
+func (c *PogodocClient) GenerateDocumentToFile(gdProps GenerateDocumentProps, path string, ctx context.Context, opts ...PollOptions) (*GetJobStatusResponse, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("creating file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	jobStatus, err := c.GenerateDocumentToWriter(gdProps, file, ctx, opts...)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("writing file: %w", closeErr)
+	}
+	if err != nil {
+		return jobStatus, err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return jobStatus, fmt.Errorf("writing file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return jobStatus, fmt.Errorf("writing file: %w", err)
+	}
+	return jobStatus, nil
+}
+
+// config returns the configuration the client was initialized with.
+func (c *PogodocClient) config() *config {
+	if c.transport == nil {
+		return newConfig(nil)
+	}
+	return c.transport.config
+}
+
+// logger returns the logger the client was initialized with,
+// or a logger discarding everything.
+func (c *PogodocClient) logger() *slog.Logger {
+	return c.config().logger
+}
+
+// metrics returns the metrics the client was initialized with,
+// or a no-op implementation.
+func (c *PogodocClient) metrics() Metrics {
+	return c.config().metrics
+}
+
+// transfer sends a request against a presigned URL through the transport of the
+// client, with retries. The endpoint names the request in metrics.
+// Error responses are returned as-is.
+func (c *PogodocClient) transfer(req *http.Request, endpoint string) (*http.Response, error) {
+	t := c.transport
+	if t == nil {
+		t = &transport{config: c.config()}
+	}
+	return t.roundTrip(req, endpoint, t.config)
+}
@@ -0,0 +1,163 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingClient records how many requests went through the configured HTTPClient.
+type countingClient struct {
+	calls atomic.Int32
+}
+
+func (c *countingClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls.Add(1)
+	return http.DefaultClient.Do(req)
+}
+
+func TestDownloadResult(t *testing.T) {
+	t.Run("retries transient failures with the configured HTTPClient", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		}))
+		defer server.Close()
+
+		httpClient := &countingClient{}
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, client.DownloadResult(server.URL+"/doc.pdf", &buf, context.Background()))
+		assert.Equal(t, "%PDF-1.7", buf.String())
+		assert.Equal(t, int32(2), httpClient.calls.Load())
+	})
+
+	t.Run("reports non-retryable failures", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		var buf bytes.Buffer
+		err := newTestClient(t, server.URL).DownloadResult(server.URL+"/missing.pdf", &buf, context.Background())
+		require.ErrorContains(t, err, "404")
+	})
+
+	t.Run("detects truncated bodies", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", strconv.Itoa(100))
+			_, _ = w.Write([]byte("%PDF"))
+		}))
+		defer server.Close()
+
+		var buf bytes.Buffer
+		err := newTestClient(t, server.URL).DownloadResult(server.URL+"/doc.pdf", &buf, context.Background())
+		require.Error(t, err)
+	})
+
+	// truncatingServer cuts the body of its first response short.
+	truncatingServer := func(t *testing.T) (*httptest.Server, *atomic.Int32) {
+		calls := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "8")
+			if calls.Add(1) == 1 {
+				_, _ = w.Write([]byte("%PD"))
+				return
+			}
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	t.Run("restarts a copy that fails partway into a buffer", func(t *testing.T) {
+		server, calls := truncatingServer(t)
+
+		buf := bytes.NewBufferString("existing:")
+		require.NoError(t, newTestClient(t, server.URL).DownloadResult(server.URL+"/doc.pdf", buf, context.Background()))
+		assert.Equal(t, "existing:%PDF-1.7", buf.String())
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("restarts a copy that fails partway into a file", func(t *testing.T) {
+		server, calls := truncatingServer(t)
+
+		file, err := os.Create(filepath.Join(t.TempDir(), "doc.pdf"))
+		require.NoError(t, err)
+		defer file.Close()
+		require.NoError(t, newTestClient(t, server.URL).DownloadResult(server.URL+"/doc.pdf", file, context.Background()))
+
+		content, err := os.ReadFile(file.Name())
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.7", string(content))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("does not restart writers that cannot be rewound", func(t *testing.T) {
+		server, calls := truncatingServer(t)
+
+		var output strings.Builder
+		err := newTestClient(t, server.URL).DownloadResult(server.URL+"/doc.pdf", &output, context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestGenerateDocumentToFile(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("POST /documents/init", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(InitializeRenderJobResponse{JobId: "job"}))
+	})
+	mux.HandleFunc("POST /documents/{jobId}/render", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(StartRenderJobResponse{JobId: r.PathValue("jobId")}))
+	})
+	mux.HandleFunc("GET /jobs/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(GetJobStatusResponse{
+			JobId:  r.PathValue("jobId"),
+			Status: "done",
+			Output: &GetJobStatusResponseOutput{
+				Data: &GetJobStatusResponseOutputData{Url: "http://" + r.Host + "/output.pdf"},
+			},
+		}))
+	})
+	mux.HandleFunc("GET /output.pdf", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("%PDF-1.7"))
+	})
+
+	path := filepath.Join(t.TempDir(), "invoice.pdf")
+	jobStatus, err := newTestClient(t, server.URL).GenerateDocumentToFile(GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			Type:   InitializeRenderJobRequestTypeHtml,
+			Target: InitializeRenderJobRequestTargetPdf,
+		},
+	}, path, context.Background(), PollOptions{InitialDelay: -1, Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "job", jobStatus.JobId)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7", string(content))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should be cleaned up")
+}
@@ -0,0 +1,141 @@
+package pogodoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DataEncoder encodes render data before it is uploaded to the presigned data URL.
+// Implement it to use a custom serialization. The encoded data is buffered in memory,
+// as the upload needs its Content-Length; to upload large JSON datasets without
+// buffering them, pass them to JSONEncoder as an io.ReadSeeker such as an *os.File.
+type DataEncoder interface {
+	// ContentType returns the MIME type of the encoded data.
+	ContentType() string
+	// Encode writes the encoded form of data to w.
+	Encode(w io.Writer, data interface{}) error
+}
+
+// JSONEncoder is the default DataEncoder. It marshals maps and structs according to
+// their json tags. Pre-encoded values (json.RawMessage, []byte and io.Reader) are
+// written as-is, so they are not encoded twice; byte slices are validated first.
+// An io.ReadSeeker is streamed to the upload rather than buffered.
+type JSONEncoder struct{}
+
+// ContentType implements DataEncoder.
+func (JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Encode implements DataEncoder.
+func (JSONEncoder) Encode(w io.Writer, data interface{}) error {
+	switch value := data.(type) {
+	case nil:
+		_, err := io.WriteString(w, "{}")
+		return err
+	case json.RawMessage:
+		return writeRawJSON(w, value)
+	case []byte:
+		return writeRawJSON(w, value)
+	case io.Reader:
+		_, err := io.Copy(w, value)
+		return err
+	default:
+		return json.NewEncoder(w).Encode(data)
+	}
+}
+
+func writeRawJSON(w io.Writer, raw []byte) error {
+	if !json.Valid(raw) {
+		return fmt.Errorf("data is not valid JSON")
+	}
+	_, err := w.Write(raw)
+	return err
+}
+
+// encodeData encodes data with the given encoder, falling back to JSONEncoder.
+// It returns the payload to upload and its content type.
+// Presigned uploads need their Content-Length up front, so the encoded data is
+// buffered in memory, except for an io.ReadSeeker passed through by JSONEncoder:
+// its size is known, so it is streamed to the upload as-is.
+func encodeData(encoder DataEncoder, data interface{}) (FileStreamProps, string, error) {
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+	if reader, ok := data.(io.ReadSeeker); ok && encoder == DataEncoder(JSONEncoder{}) {
+		size, err := remainingSize(reader)
+		if err != nil {
+			return FileStreamProps{}, "", &StepError{Step: StepEncodeData, Err: err}
+		}
+		return FileStreamFromReader(reader, size), encoder.ContentType(), nil
+	}
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, data); err != nil {
+		return FileStreamProps{}, "", &StepError{Step: StepEncodeData, Err: err}
+	}
+	return FileStreamFromBytes(buf.Bytes()), encoder.ContentType(), nil
+}
+
+// remainingSize returns the number of bytes left to read from r.
+func remainingSize(r io.Seeker) (int64, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - offset, nil
+}
+
+// encodeDataMap encodes data for the immediate render endpoint, which carries
+// the data inline in its JSON request as an object. Data that cannot be sent
+// that way, like a custom encoding or a JSON array, is an error rather than
+// being dropped.
+func encodeDataMap(encoder DataEncoder, data interface{}) (map[string]interface{}, error) {
+	if encoder != nil && encoder.ContentType() != (JSONEncoder{}).ContentType() {
+		return nil, &StepError{Step: StepEncodeData, Err: fmt.Errorf("immediate renders need JSON data, not %s", encoder.ContentType())}
+	}
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, data); err != nil {
+		return nil, &StepError{Step: StepEncodeData, Err: err}
+	}
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, &StepError{Step: StepEncodeData, Err: fmt.Errorf("data is not valid JSON: %w", err)}
+	}
+	dataMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, &StepError{Step: StepEncodeData, Err: fmt.Errorf("immediate renders need data encoding to a JSON object, got %s", jsonKind(value))}
+	}
+	return dataMap, nil
+}
+
+// jsonKind names the kind of a decoded JSON value.
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,138 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoice struct {
+	Number   string `json:"number"`
+	Customer string `json:"customer"`
+	Internal string `json:"-"`
+}
+
+func TestJSONEncoder(t *testing.T) {
+	tests := []struct {
+		description string
+		give        interface{}
+		want        string
+		wantErr     bool
+	}{
+		{description: "nil data encodes as an empty object", give: nil, want: `{}`},
+		{description: "maps are marshalled", give: map[string]interface{}{"name": "John Doe"}, want: `{"name":"John Doe"}`},
+		{description: "structs follow json tags", give: invoice{Number: "1", Customer: "ACME", Internal: "x"}, want: `{"number":"1","customer":"ACME"}`},
+		{description: "raw messages are not encoded twice", give: json.RawMessage(`{"a": [1, 2]}`), want: `{"a": [1, 2]}`},
+		{description: "invalid raw messages are rejected", give: json.RawMessage(`{"a":`), wantErr: true},
+		{description: "readers are streamed as-is", give: strings.NewReader(`{"streamed":true}`), want: `{"streamed":true}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := JSONEncoder{}.Encode(&buf, tc.give)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestStartGenerateDocumentUploadsJSON(t *testing.T) {
+	var uploaded []byte
+	var uploadedContentType string
+	var uploadedLength int64
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("POST /documents/init", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(InitializeRenderJobResponse{
+			JobId:                  "job",
+			PresignedDataUploadUrl: String(server.URL + "/upload/data"),
+		}))
+	})
+	mux.HandleFunc("PUT /upload/data", func(w http.ResponseWriter, r *http.Request) {
+		uploadedContentType = r.Header.Get("Content-Type")
+		uploadedLength = r.ContentLength
+		uploaded, _ = io.ReadAll(r.Body)
+	})
+	mux.HandleFunc("POST /documents/{jobId}/render", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(StartRenderJobResponse{JobId: r.PathValue("jobId")}))
+	})
+
+	client := newTestClient(t, server.URL)
+
+	t.Run("map data", func(t *testing.T) {
+		_, err := client.StartGenerateDocument(GenerateDocumentProps{
+			InitializeRenderJobRequest: InitializeRenderJobRequest{
+				Type:   InitializeRenderJobRequestTypeHtml,
+				Target: InitializeRenderJobRequestTargetPdf,
+				Data:   map[string]interface{}{"name": "John Doe"},
+			},
+		}, context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", uploadedContentType)
+		assert.JSONEq(t, `{"name":"John Doe"}`, string(uploaded))
+	})
+
+	t.Run("struct data", func(t *testing.T) {
+		_, err := client.StartGenerateDocument(GenerateDocumentProps{
+			InitializeRenderJobRequest: InitializeRenderJobRequest{
+				Type:   InitializeRenderJobRequestTypeHtml,
+				Target: InitializeRenderJobRequestTargetPdf,
+			},
+			Data: invoice{Number: "42", Customer: "ACME"},
+		}, context.Background())
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"number":"42","customer":"ACME"}`, string(uploaded))
+	})
+	t.Run("file data is streamed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"name":"John Doe"}`), 0o600))
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		_, err = client.StartGenerateDocument(GenerateDocumentProps{
+			InitializeRenderJobRequest: InitializeRenderJobRequest{
+				Type:   InitializeRenderJobRequestTypeHtml,
+				Target: InitializeRenderJobRequestTargetPdf,
+			},
+			Data: file,
+		}, context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(`{"name":"John Doe"}`)), uploadedLength)
+		assert.JSONEq(t, `{"name":"John Doe"}`, string(uploaded))
+	})
+}
+
+func TestEncodeDataStreamsSeekableReaders(t *testing.T) {
+	reader := strings.NewReader(`{"streamed":true}`)
+	_, err := reader.Seek(1, io.SeekStart)
+	require.NoError(t, err)
+
+	stream, contentType, err := encodeData(nil, reader)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, int64(reader.Len()), stream.Size())
+	assert.Equal(t, 16, reader.Len(), "the reader should only be read by the upload")
+
+	// Other encoders or readers are buffered, to know the Content-Length.
+	stream, _, err = encodeData(nil, io.MultiReader(strings.NewReader(`{"buffered":true}`)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(`{"buffered":true}`)), stream.Size())
+}
@@ -0,0 +1,163 @@
+package pogodoc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors to test the errors returned by the client against with errors.Is.
+// Errors caused by an API response also carry the *APIError, with its status code,
+// which can be retrieved with errors.As. Errors caused by a response to a presigned
+// URL carry a *TransferError instead, which does not match the API sentinels.
+var (
+	// ErrUnauthorized is matched by 401 and 403 API responses.
+	ErrUnauthorized = errors.New("pogodoc: unauthorized")
+	// ErrNotFound is matched by 404 API responses.
+	ErrNotFound = errors.New("pogodoc: not found")
+	// ErrRateLimited is matched by 429 API responses.
+	ErrRateLimited = errors.New("pogodoc: rate limited")
+	// ErrValidation is matched by 400 and 422 API responses.
+	ErrValidation = errors.New("pogodoc: validation failed")
+	// ErrUploadFailed is matched by failed uploads to presigned URLs.
+	ErrUploadFailed = errors.New("pogodoc: upload failed")
+	// ErrJobFailed is matched by *RenderJobError.
+	ErrJobFailed = errors.New("pogodoc: render job failed")
+)
+
+// Step identifies a stage of the multi-step template and render workflows.
+type Step string
+
+// Steps of the template save and update workflows.
+const (
+	StepInitializeTemplate Step = "initializing template creation"
+	StepUploadTemplate     Step = "uploading template"
+	StepExtractTemplate    Step = "extracting template files"
+	StepGeneratePreviews   Step = "generating template previews"
+	StepSaveTemplate       Step = "saving created template"
+	StepUpdateTemplate     Step = "updating template"
+)
+
+// Steps of the render workflow.
+const (
+	StepEncodeData       Step = "encoding data"
+	StepInitializeRender Step = "initializing document render"
+	StepUploadData       Step = "uploading document data"
+	StepUploadHTML       Step = "uploading document template"
+	StepStartRender      Step = "starting render"
+	StepGetJobStatus     Step = "getting job status"
+	StepDownloadResult   Step = "downloading result"
+)
+
+// StepError is returned when a step of a multi-step workflow fails.
+// It wraps the error of the step, so errors.Is and errors.As see through it.
+type StepError struct {
+	Step Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return string(e.Step) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the failed step.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors of the upload steps match ErrUploadFailed.
+func (e *StepError) Is(target error) bool {
+	if target != ErrUploadFailed {
+		return false
+	}
+	switch e.Step {
+	case StepUploadTemplate, StepUploadData, StepUploadHTML:
+		return true
+	}
+	return false
+}
+
+// maxErrorBodySize limits how much of an error response is kept in a *TransferError.
+const maxErrorBodySize = 4 << 10
+
+// TransferError is returned when an upload to or a download from a presigned
+// URL fails with an error response from the storage service. Unlike *APIError,
+// it does not match ErrUnauthorized, ErrNotFound, ErrRateLimited or
+// ErrValidation: a 403 from S3 means the presigned URL expired or its signature
+// was rejected, not that the API token is invalid. Failed uploads still match
+// ErrUploadFailed.
+//
+// When the response body holds an S3 XML error, its code, message and request
+// ID are decoded into the corresponding fields. The beginning of the raw body
+// is always kept in Body.
+type TransferError struct {
+	StatusCode int
+	// Code is the error code of the storage service, e.g. "AccessDenied".
+	Code string
+	// Message is the human-readable message of the storage service.
+	Message string
+	// RequestId identifies the failed request, for support requests.
+	RequestId string
+	// Header holds the headers of the error response.
+	Header http.Header
+	// Body is the beginning of the raw body of the error response.
+	Body []byte
+}
+
+// s3Error is the XML error body returned by S3.
+type s3Error struct {
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+	RequestId string `xml:"RequestId"`
+}
+
+// newTransferError builds a *TransferError from an error response to a request
+// against a presigned URL, keeping the beginning of its body.
+func newTransferError(resp *http.Response) *TransferError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	transferError := &TransferError{StatusCode: resp.StatusCode, Header: resp.Header}
+	if len(body) > 0 {
+		transferError.Body = body
+		var s3Err s3Error
+		if err := xml.Unmarshal(body, &s3Err); err == nil {
+			transferError.Code = s3Err.Code
+			transferError.Message = s3Err.Message
+			transferError.RequestId = s3Err.RequestId
+		}
+	}
+	if transferError.RequestId == "" {
+		for _, name := range requestIdHeaders {
+			if requestId := resp.Header.Get(name); requestId != "" {
+				transferError.RequestId = requestId
+				break
+			}
+		}
+	}
+	return transferError
+}
+
+// Error returns the status code and the code and message of the storage
+// service, e.g. `403 AccessDenied: Request has expired [request ID: abc]`,
+// falling back to the raw body.
+func (e *TransferError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", e.StatusCode)
+	switch {
+	case e.Code != "" || e.Message != "":
+		if e.Code != "" {
+			b.WriteString(" " + e.Code)
+		}
+		if e.Message != "" {
+			b.WriteString(": " + e.Message)
+		}
+	case len(e.Body) > 0:
+		b.WriteString(": " + strings.TrimSpace(string(e.Body)))
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&b, " [request ID: %s]", e.RequestId)
+	}
+	return b.String()
+}
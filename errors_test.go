@@ -0,0 +1,128 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	gdProps := GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			Type:   InitializeRenderJobRequestTypeHtml,
+			Target: InitializeRenderJobRequestTargetPdf,
+		},
+		Data: map[string]interface{}{"name": "Pogodoc"},
+	}
+
+	t.Run("api error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Request-Id", "req_1")
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"message":"Template not found","code":"not_found"}`))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token")
+		require.NoError(t, err)
+
+		_, err = client.StartGenerateDocument(gdProps, context.Background())
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrUnauthorized)
+
+		var stepErr *StepError
+		require.ErrorAs(t, err, &stepErr)
+		assert.Equal(t, StepInitializeRender, stepErr.Step)
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "Template not found", apiErr.Message)
+		assert.Equal(t, "not_found", apiErr.Code)
+		assert.Equal(t, "req_1", apiErr.RequestId)
+		assert.Equal(t, "application/json", apiErr.Header.Get("Content-Type"))
+		assert.EqualError(t, err, "initializing document render: 404 not_found: Template not found [request ID: req_1]")
+	})
+
+	t.Run("upload failed", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+		mux.HandleFunc("POST /documents/init", func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewEncoder(w).Encode(InitializeRenderJobResponse{
+				JobId:                  "job",
+				PresignedDataUploadUrl: String("http://" + r.Host + "/upload"),
+			}))
+		})
+		mux.HandleFunc("PUT /upload", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusForbidden)
+			_, err := w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>AccessDenied</Code><Message>Request has expired</Message><RequestId>req_1</RequestId></Error>`))
+			assert.NoError(t, err)
+		})
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token")
+		require.NoError(t, err)
+
+		_, err = client.StartGenerateDocument(gdProps, context.Background())
+		assert.ErrorIs(t, err, ErrUploadFailed)
+		assert.NotErrorIs(t, err, ErrUnauthorized)
+
+		var transferErr *TransferError
+		require.ErrorAs(t, err, &transferErr)
+		assert.Equal(t, http.StatusForbidden, transferErr.StatusCode)
+		assert.Equal(t, "AccessDenied", transferErr.Code)
+		assert.Equal(t, "Request has expired", transferErr.Message)
+		assert.Equal(t, "req_1", transferErr.RequestId)
+		assert.ErrorContains(t, err, "403 AccessDenied: Request has expired [request ID: req_1]")
+
+		var apiErr *APIError
+		assert.False(t, errors.As(err, &apiErr))
+
+		var stepErr *StepError
+		require.ErrorAs(t, err, &stepErr)
+		assert.Equal(t, StepUploadData, stepErr.Step)
+	})
+
+	t.Run("job failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewEncoder(w).Encode(GetJobStatusResponse{JobId: "job", Status: "failed"}))
+		}))
+		defer server.Close()
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token")
+		require.NoError(t, err)
+
+		_, err = client.PollForJobCompletion("job", context.Background(), PollOptions{InitialDelay: -1, Interval: time.Millisecond})
+		assert.ErrorIs(t, err, ErrJobFailed)
+		assert.NotErrorIs(t, err, ErrUploadFailed)
+	})
+
+	t.Run("download failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token")
+		require.NoError(t, err)
+
+		err = client.DownloadResult(server.URL+"/output.pdf", nil, context.Background())
+		assert.NotErrorIs(t, err, ErrRateLimited)
+
+		var transferErr *TransferError
+		require.ErrorAs(t, err, &transferErr)
+		assert.Equal(t, http.StatusTooManyRequests, transferErr.StatusCode)
+		assert.EqualError(t, err, "downloading result: 429")
+
+		var stepErr *StepError
+		require.ErrorAs(t, err, &stepErr)
+		assert.Equal(t, StepDownloadResult, stepErr.Step)
+	})
+}
@@ -0,0 +1,112 @@
+package pogodoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// errStreamNotReplayable is returned when an upload has to be retried but its
+// payload comes from a reader that cannot be rewound.
+var errStreamNotReplayable = errors.New("pogodoc: file stream cannot be replayed")
+
+// FileStreamFromBytes returns FileStreamProps for an in-memory payload.
+func FileStreamFromBytes(payload []byte) FileStreamProps {
+	return FileStreamProps{
+		payload:       payload,
+		payloadLength: len(payload),
+	}
+}
+
+// FileStreamFromReader returns FileStreamProps that stream size bytes from r.
+// The payload is not buffered, so uploads can only be retried if r also implements io.Seeker.
+// The caller remains responsible for closing r once the upload completes.
+func FileStreamFromReader(r io.Reader, size int64) FileStreamProps {
+	var (
+		mu     sync.Mutex
+		opened bool
+		offset int64
+	)
+	return FileStreamProps{
+		payloadLength: int(size),
+		open: func() (io.ReadCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			seeker, canSeek := r.(io.Seeker)
+			if !opened {
+				opened = true
+				if canSeek {
+					var err error
+					if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+						return nil, err
+					}
+				}
+			} else {
+				if !canSeek {
+					return nil, errStreamNotReplayable
+				}
+				if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+			}
+			return io.NopCloser(io.LimitReader(r, size)), nil
+		},
+	}
+}
+
+// FileStreamFromFile returns FileStreamProps that stream the file at filePath.
+// The file is opened when the upload starts, and reopened if the upload is retried.
+func FileStreamFromFile(filePath string) (FileStreamProps, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return FileStreamProps{}, err
+	}
+	if info.IsDir() {
+		return FileStreamProps{}, fmt.Errorf("error: %s is a directory", filePath)
+	}
+	if info.Size() == 0 {
+		return FileStreamProps{}, fmt.Errorf("error: File is empty")
+	}
+	return FileStreamProps{
+		payloadLength: int(info.Size()),
+		open: func() (io.ReadCloser, error) {
+			return os.Open(filePath)
+		},
+	}, nil
+}
+
+// Size returns the length of the payload in bytes.
+func (f FileStreamProps) Size() int64 {
+	return int64(f.payloadLength)
+}
+
+// WithProgress returns a copy of the FileStreamProps that reports upload progress to progress.
+// If the upload is retried, progress starts over from zero.
+func (f FileStreamProps) WithProgress(progress ProgressFunc) FileStreamProps {
+	f.progress = progress
+	return f
+}
+
+// body returns a fresh reader over the payload.
+func (f FileStreamProps) body() (io.ReadCloser, error) {
+	var (
+		body io.ReadCloser
+		err  error
+	)
+	if f.open != nil {
+		body, err = f.open()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		body = io.NopCloser(bytes.NewReader(f.payload))
+	}
+	if f.progress != nil {
+		body = &progressReader{ReadCloser: body, total: f.Size(), progress: f.progress}
+	}
+	return body, nil
+}
@@ -0,0 +1,88 @@
+package pogodoc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyUploadServer fails the first upload attempt and records every body it receives.
+func newFlakyUploadServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestFileStreamProps(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("FromBytes", func(t *testing.T) {
+		fsProps := FileStreamFromBytes([]byte("zip bytes"))
+		assert.Equal(t, int64(9), fsProps.Size())
+
+		server, bodies := newFlakyUploadServer(t)
+		require.NoError(t, newTestClient(t, server.URL).UploadToPresignedURL(ctx, server.URL, fsProps, "application/zip"))
+		assert.Equal(t, []string{"zip bytes", "zip bytes"}, *bodies)
+	})
+
+	t.Run("FromReader replays seekable readers", func(t *testing.T) {
+		reader := strings.NewReader("skip:zip bytes")
+		_, err := reader.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+		fsProps := FileStreamFromReader(reader, 9)
+
+		server, bodies := newFlakyUploadServer(t)
+		require.NoError(t, newTestClient(t, server.URL).UploadToPresignedURL(ctx, server.URL, fsProps, "application/zip"))
+		assert.Equal(t, []string{"zip bytes", "zip bytes"}, *bodies)
+	})
+
+	t.Run("FromReader cannot retry plain readers", func(t *testing.T) {
+		fsProps := FileStreamFromReader(io.MultiReader(strings.NewReader("zip bytes")), 9)
+
+		server, bodies := newFlakyUploadServer(t)
+		err := newTestClient(t, server.URL).UploadToPresignedURL(ctx, server.URL, fsProps, "application/zip")
+		require.ErrorIs(t, err, errStreamNotReplayable)
+		assert.Equal(t, []string{"zip bytes"}, *bodies)
+	})
+
+	t.Run("FromFile streams from disk", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "template.zip")
+		require.NoError(t, os.WriteFile(path, []byte("zip bytes"), 0o644))
+
+		fsProps, err := FileStreamFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(9), fsProps.Size())
+
+		server, bodies := newFlakyUploadServer(t)
+		require.NoError(t, newTestClient(t, server.URL).UploadToPresignedURL(ctx, server.URL, fsProps, "application/zip"))
+		assert.Equal(t, []string{"zip bytes", "zip bytes"}, *bodies)
+	})
+
+	t.Run("FromFile rejects empty and missing files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.zip")
+		require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+		_, err := FileStreamFromFile(path)
+		require.Error(t, err)
+
+		_, err = FileStreamFromFile(filepath.Join(t.TempDir(), "missing.zip"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
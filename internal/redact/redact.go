@@ -0,0 +1,57 @@
+// Package redact lists the credentials the SDK keeps out of its logs and of
+// the cassettes recorded by pogodoctest, so both redact the same data.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces redacted credentials.
+const Placeholder = "REDACTED"
+
+// Headers are the headers carrying credentials.
+var Headers = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Amz-Security-Token",
+}
+
+// queryParameters are the query parameters carrying the signature and
+// credentials of presigned URLs, matched case-insensitively as substrings.
+// They cover SigV4 (X-Amz-Signature, X-Amz-Credential) and SigV2 (Signature,
+// AWSAccessKeyId) URLs.
+var queryParameters = []string{
+	"signature",
+	"credential",
+	"awsaccesskeyid",
+	"security-token",
+	"token",
+}
+
+// IsQueryParameter reports whether the query parameter with the given name
+// carries a signature or credentials.
+func IsQueryParameter(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range queryParameters {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryParameterPattern matches the sensitive query parameters of URLs in
+// text, including URLs in JSON bodies where & is escaped as \u0026. The first
+// group holds everything up to the value, which is left for redaction.
+var QueryParameterPattern = regexp.MustCompile(queryParameterPattern())
+
+func queryParameterPattern() string {
+	names := make([]string, len(queryParameters))
+	for i, name := range queryParameters {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	return `(?i)((?:[?&]|\\u0026)[\w.-]*(?:` + strings.Join(names, "|") + `)[\w.-]*=)[^&"'\s\\<]*`
+}
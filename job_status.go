@@ -0,0 +1,67 @@
+package pogodoc
+
+import "fmt"
+
+// JobStatus is the status of a render job as reported by GetJobStatus.
+type JobStatus string
+
+// Render Job Status Constants
+const (
+	JobStatusPending    JobStatus = "pending"
+	JobStatusInProgress JobStatus = "in-progress"
+	JobStatusDone       JobStatus = "done"
+	JobStatusFailed     JobStatus = "failed"
+)
+
+// IsTerminal reports whether a job in this status will not change status again.
+func (s JobStatus) IsTerminal() bool {
+	return s == JobStatusDone || s == JobStatusFailed
+}
+
+// StatusOf classifies a job status response. A response that reports an error
+// or Success=false is treated as JobStatusFailed regardless of its status field.
+func StatusOf(jobStatus *GetJobStatusResponse) JobStatus {
+	if jobStatus == nil {
+		return JobStatusPending
+	}
+	if jobStatus.Error != nil || (jobStatus.Success != nil && !*jobStatus.Success) {
+		return JobStatusFailed
+	}
+	return JobStatus(jobStatus.Status)
+}
+
+// RenderJobError is returned when a render job reaches a failed terminal state.
+type RenderJobError struct {
+	JobId      string
+	TemplateId string
+	Target     string
+	// Message is the error reported by the Pogodoc API, if any.
+	Message string
+}
+
+func (e *RenderJobError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("render job %s failed", e.JobId)
+	}
+	return fmt.Sprintf("render job %s failed: %s", e.JobId, e.Message)
+}
+
+// Is makes every *RenderJobError match ErrJobFailed.
+func (e *RenderJobError) Is(target error) bool {
+	return target == ErrJobFailed
+}
+
+// newRenderJobError builds a *RenderJobError from a failed job status response.
+func newRenderJobError(jobStatus *GetJobStatusResponse) *RenderJobError {
+	err := &RenderJobError{
+		JobId:  jobStatus.JobId,
+		Target: jobStatus.Target,
+	}
+	if jobStatus.TemplateId != nil {
+		err.TemplateId = *jobStatus.TemplateId
+	}
+	if jobStatus.Error != nil {
+		err.Message = *jobStatus.Error
+	}
+	return err
+}
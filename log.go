@@ -0,0 +1,95 @@
+package pogodoc
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Pogodoc/pogodoc-go/internal/redact"
+)
+
+// redacted replaces the credentials removed from logged requests.
+const redacted = redact.Placeholder
+
+// redactURL returns u as a string with the signature and credentials of
+// presigned URLs, and any user info, redacted.
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	if redactedURL.User != nil {
+		redactedURL.User = url.User(redacted)
+	}
+	if redactedURL.RawQuery == "" {
+		return redactedURL.String()
+	}
+	query := redactedURL.Query()
+	for name := range query {
+		if redact.IsQueryParameter(name) {
+			query.Set(name, redacted)
+		}
+	}
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+// redactHeader returns a copy of header with credentials redacted.
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := header.Clone()
+	for _, name := range redact.Headers {
+		if redactedHeader.Get(name) != "" {
+			redactedHeader.Set(name, redacted)
+		}
+	}
+	return redactedHeader
+}
+
+// logRequest logs that req is about to be sent.
+func logRequest(logger *slog.Logger, req *http.Request) {
+	ctx := req.Context()
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	logger.DebugContext(
+		ctx,
+		"sending request",
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Any("headers", redactHeader(req.Header)),
+		slog.Int64("content_length", req.ContentLength),
+	)
+}
+
+// logResponse logs the outcome of req, sent at start, after all retries.
+func logResponse(logger *slog.Logger, req *http.Request, resp *http.Response, err error, start time.Time) {
+	attrs := []any{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	ctx := req.Context()
+	switch {
+	case err != nil:
+		logger.WarnContext(ctx, "request failed", append(attrs, slog.Any("error", err))...)
+	case resp.StatusCode >= http.StatusBadRequest:
+		logger.WarnContext(ctx, "request failed", append(attrs, slog.Int("status", resp.StatusCode))...)
+	default:
+		logger.DebugContext(ctx, "received response", append(attrs, slog.Int("status", resp.StatusCode))...)
+	}
+}
+
+// logRetry logs a retry that is about to happen.
+func logRetry(logger *slog.Logger, event RetryEvent) {
+	attrs := []any{
+		slog.String("method", event.Request.Method),
+		slog.String("url", redactURL(event.Request.URL)),
+		slog.Uint64("attempt", uint64(event.Attempt)),
+		slog.Duration("delay", event.Delay),
+	}
+	if event.Response != nil {
+		attrs = append(attrs, slog.Int("status", event.Response.StatusCode))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+	logger.WarnContext(event.Request.Context(), "retrying request", attrs...)
+}
@@ -0,0 +1,42 @@
+package pogodoc
+
+import "time"
+
+// Metrics receives measurements from the SDK, e.g. to export them to Prometheus.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest records a request once it completed, including its retries.
+	// The endpoint names the API operation (e.g. "documents.InitializeRenderJob"),
+	// or "upload" and "download" for presigned URLs. The status code is 0 if no
+	// response was received.
+	ObserveRequest(endpoint string, statusCode int, duration time.Duration)
+	// IncRetry records a retry caused by the given status code, or by a network
+	// error if the status code is 0.
+	IncRetry(statusCode int)
+	// AddUploadBytes records bytes uploaded to a presigned URL.
+	AddUploadBytes(bytes int64)
+	// ObservePollAttempts records how many status checks were made for a job
+	// before polling stopped, with the last status seen.
+	ObservePollAttempts(attempts int, status string)
+	// ObserveRenderTime records the render time reported by the server for a
+	// finished job, in milliseconds as returned by the API.
+	ObserveRenderTime(target string, renderTime float64)
+}
+
+// NoopMetrics is a Metrics implementation that discards every measurement.
+type NoopMetrics struct{}
+
+// ObserveRequest implements Metrics.
+func (NoopMetrics) ObserveRequest(string, int, time.Duration) {}
+
+// IncRetry implements Metrics.
+func (NoopMetrics) IncRetry(int) {}
+
+// AddUploadBytes implements Metrics.
+func (NoopMetrics) AddUploadBytes(int64) {}
+
+// ObservePollAttempts implements Metrics.
+func (NoopMetrics) ObservePollAttempts(int, string) {}
+
+// ObserveRenderTime implements Metrics.
+func (NoopMetrics) ObserveRenderTime(string, float64) {}
@@ -0,0 +1,207 @@
+package pogodoc
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Pogodoc/pogodoc-go/client/core"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// config holds the options of the behavior PogodocClient adds around the
+// generated client: retries, middleware, logging, metrics and tracing.
+type config struct {
+	maxAttempts    uint
+	retry          RetryOptions
+	middleware     []Middleware
+	tracerProvider trace.TracerProvider
+	logger         *slog.Logger
+	metrics        Metrics
+}
+
+// newConfig returns the configuration resulting from opts.
+func newConfig(opts []*sdkOption) *config {
+	return new(config).with(opts)
+}
+
+// attempts returns the maximum number of attempts of a request.
+func (c *config) attempts() uint {
+	if c.maxAttempts > 0 {
+		return c.maxAttempts
+	}
+	return defaultRetryAttempts
+}
+
+// retryOptions returns the retry options with their defaults filled in.
+func (c *config) retryOptions() RetryOptions {
+	retry := c.retry
+	if retry.BaseDelay <= 0 {
+		retry.BaseDelay = minRetryDelay
+	}
+	if retry.MaxDelay <= 0 {
+		retry.MaxDelay = maxRetryDelay
+	}
+	if retry.Jitter == nil {
+		retry.Jitter = DefaultJitter
+	}
+	if retry.MaxWait <= 0 {
+		retry.MaxWait = defaultMaxRetryWait
+	}
+	if retry.Policy == nil {
+		retry.Policy = DefaultRetryPolicy{StatusCodes: retry.StatusCodes}
+	}
+	return retry
+}
+
+// with returns a copy of c with opts applied.
+func (c *config) with(opts []*sdkOption) *config {
+	merged := *c
+	// Options appending to the middleware must not write to the slice of c.
+	merged.middleware = slices.Clip(merged.middleware)
+	for _, opt := range opts {
+		opt.apply(&merged)
+	}
+	if merged.logger == nil {
+		merged.logger = slog.New(slog.DiscardHandler)
+	}
+	if merged.metrics == nil {
+		merged.metrics = NoopMetrics{}
+	}
+	return &merged
+}
+
+// sdkOption is a RequestOption configuring the behavior PogodocClient adds
+// around the generated client. It embeds a core.MaxAttemptsOption only to
+// satisfy RequestOption: its zero value leaves the generated client as is.
+type sdkOption struct {
+	*core.MaxAttemptsOption
+	apply func(*config)
+}
+
+func newSDKOption(apply func(*config)) *sdkOption {
+	return &sdkOption{MaxAttemptsOption: new(core.MaxAttemptsOption), apply: apply}
+}
+
+// splitOptions separates the options of PogodocClient from the options
+// handled by the generated client.
+func splitOptions(opts []RequestOption) ([]*sdkOption, []RequestOption) {
+	var sdkOpts []*sdkOption
+	var clientOpts []RequestOption
+	for _, opt := range opts {
+		if sdkOpt, ok := opt.(*sdkOption); ok {
+			sdkOpts = append(sdkOpts, sdkOpt)
+			continue
+		}
+		clientOpts = append(clientOpts, opt)
+	}
+	return sdkOpts, clientOpts
+}
+
+// WithMaxRetryWait configures the longest delay the client is willing to wait
+// when the server asks it to back off with a Retry-After or X-RateLimit-Reset
+// header. If the server asks for a longer delay, the request is not retried.
+func WithMaxRetryWait(maxRetryWait time.Duration) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.MaxWait = maxRetryWait
+	})
+}
+
+// WithRetryHook registers a hook that is called before every retry, with
+// the delay chosen before the next attempt.
+func WithRetryHook(retryHook RetryHook) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.Hook = retryHook
+	})
+}
+
+// WithRetryPolicy configures which failed attempts are retried. The default,
+// DefaultRetryPolicy, retries 408, 429 and 5xx responses, and transient
+// network errors for idempotent requests.
+func WithRetryPolicy(retryPolicy RetryPolicy) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.Policy = retryPolicy
+	})
+}
+
+// WithRetryBaseDelay configures the delay before the first retry. Later retries
+// back off quadratically from it, waiting base × (1 + retries²), and no retry
+// waits less than it.
+func WithRetryBaseDelay(baseDelay time.Duration) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.BaseDelay = baseDelay
+	})
+}
+
+// WithRetryMaxDelay caps the back-off between retries. It does
+// not apply to delays requested by the server, see WithMaxRetryWait.
+func WithRetryMaxDelay(maxDelay time.Duration) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.MaxDelay = maxDelay
+	})
+}
+
+// WithRetryJitter configures how the back-off between retries is randomized,
+// e.g. NoJitter or FullJitter. It defaults to DefaultJitter.
+func WithRetryJitter(jitter JitterStrategy) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.Jitter = jitter
+	})
+}
+
+// WithRetryableStatusCodes replaces the response status codes retried by
+// DefaultRetryPolicy, which are 408, 429 and 5xx by default.
+// It has no effect on custom policies set with WithRetryPolicy.
+func WithRetryableStatusCodes(statusCodes ...int) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.StatusCodes = statusCodes
+	})
+}
+
+// WithRetryBudget limits the total time spent retrying a call, measured from
+// the start of the first attempt. A retry that would not start before the
+// budget runs out is not issued.
+func WithRetryBudget(budget time.Duration) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.retry.Budget = budget
+	})
+}
+
+// WithMiddleware adds middleware wrapping every HTTP request, including API
+// calls, uploads to presigned URLs and result downloads. Middleware runs in
+// the order it is added, once per attempt. Client-wide middleware wraps the
+// middleware passed to an individual call.
+func WithMiddleware(middleware ...Middleware) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.middleware = append(c.middleware, middleware...)
+	})
+}
+
+// WithTracerProvider configures the OpenTelemetry tracer provider used to
+// trace the high-level PogodocClient methods and the HTTP requests they make.
+// It defaults to the global tracer provider, which does not record anything
+// unless one is installed. It only applies when creating the client.
+func WithTracerProvider(tracerProvider trace.TracerProvider) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.tracerProvider = tracerProvider
+	})
+}
+
+// WithLogger configures the logger used to report requests, retries, uploads
+// and job progress. Requests and responses are logged at the debug level,
+// retries and failures at the warn level. The Authorization header and the
+// signatures of presigned URLs are redacted. Nothing is logged by default.
+func WithLogger(logger *slog.Logger) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.logger = logger
+	})
+}
+
+// WithMetrics configures where the client reports request latencies, retries,
+// upload sizes, poll attempts and render times. See the pogodocprom package
+// for a Prometheus implementation. Nothing is recorded by default.
+func WithMetrics(metrics Metrics) RequestOption {
+	return newSDKOption(func(c *config) {
+		c.metrics = metrics
+	})
+}
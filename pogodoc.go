@@ -9,17 +9,20 @@ package pogodoc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"time"
 
 	"github.com/Pogodoc/pogodoc-go/client/client"
+	"github.com/Pogodoc/pogodoc-go/client/core"
 	"github.com/Pogodoc/pogodoc-go/client/option"
 )
 
 // PogodocClient is a client for interacting with the Pogodoc API.
 // PogodocClientInit initializes a PogodocClient with the base URL and token from environment variables.
-func PogodocClientInit() (*PogodocClient, error) {
+// Additional request options, such as WithHTTPClient, apply to every request made by the client.
+func PogodocClientInit(opts ...RequestOption) (*PogodocClient, error) {
 	var tokenString string
 	var baseURL string
 	if os.Getenv("POGODOC_BASE_URL") != "" {
@@ -33,80 +36,95 @@ func PogodocClientInit() (*PogodocClient, error) {
 	} else {
 		return nil, fmt.Errorf("API token is required. Please provide it either as a parameter or set the POGODOC_API_TOKEN environment variable")
 	}
-	c := client.NewClient(
+	return newPogodocClient(append([]RequestOption{
 		option.WithToken(tokenString),
 		option.WithBaseURL(baseURL),
-	)
-	return &PogodocClient{Client: c}, nil
+	}, opts...)...), nil
 }
 
 // PogodocClientInitWithConfig initializes a PogodocClient with a custom base URL and token.
-func PogodocClientInitWithConfig(baseURL string, tokenString string) (*PogodocClient, error) {
-
-	c := client.NewClient(
+// Additional request options, such as WithHTTPClient, apply to every request made by the client.
+func PogodocClientInitWithConfig(baseURL string, tokenString string, opts ...RequestOption) (*PogodocClient, error) {
+	return newPogodocClient(append([]RequestOption{
 		option.WithToken(tokenString),
 		option.WithBaseURL(baseURL),
-	)
-
-	return &PogodocClient{Client: c}, nil
+	}, opts...)...), nil
 }
 
 // PogodocClientInitWithToken initializes a PogodocClient with only a token, using the default base URL.
-func PogodocClientInitWithToken(tokenString string) (*PogodocClient, error) {
-	c := client.NewClient(
+// Additional request options, such as WithHTTPClient, apply to every request made by the client.
+func PogodocClientInitWithToken(tokenString string, opts ...RequestOption) (*PogodocClient, error) {
+	return newPogodocClient(append([]RequestOption{
 		option.WithToken(tokenString),
-	)
-	return &PogodocClient{Client: c}, nil
+	}, opts...)...), nil
+}
+
+// newPogodocClient builds a PogodocClient. The generated client sends its
+// requests through the transport of the PogodocClient, which adds retries,
+// middleware, logging and metrics, so the generated client does not retry itself.
+func newPogodocClient(opts ...RequestOption) *PogodocClient {
+	c := new(PogodocClient)
+	sdkOpts, clientOpts := splitOptions(opts)
+	options := core.NewRequestOptions(clientOpts...)
+	config := newConfig(sdkOpts)
+	config.maxAttempts = options.MaxAttempts
+	// Tracing wraps every other middleware, so their time is part of the HTTP span.
+	config.middleware = append([]Middleware{c.traceMiddleware}, config.middleware...)
+	c.transport = &transport{client: options.HTTPClient, config: config}
+
+	c.Client = client.NewClient(append(clientOpts, option.WithHTTPClient(c.transport), option.WithMaxAttempts(1))...)
+	c.Templates = &templatesClient{client: c, api: c.Client.Templates}
+	c.Documents = &documentsClient{client: c, api: c.Client.Documents}
+	return c
 }
 
 // SaveTemplate is a method extension to SaveTeamplateFromFileStream to save a template from a file path to the Pogodoc service.
-// It wraps the SaveTemplateFromFileStream method.
-func (c *PogodocClient) SaveTemplate(filePath string, metadata SaveCreatedTemplateRequestTemplateInfo, ctx context.Context) (string, error) {
-	payload, err := ReadFile(filePath)
+// It wraps the SaveTemplateFromFileStream method, streaming the file from disk.
+func (c *PogodocClient) SaveTemplate(filePath string, metadata SaveCreatedTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (string, error) {
+	fsProps, err := FileStreamFromFile(filePath)
 	if err != nil {
 		return "", err
 	}
-	payloadLength := len(payload)
-	if payloadLength == 0 {
-		return "", fmt.Errorf("error: File is empty")
-	}
 
-	fsProps := FileStreamProps{
-		payload:       payload,
-		payloadLength: payloadLength,
-	}
-
-	return c.SaveTemplateFromFileStream(fsProps, metadata, ctx)
+	return c.SaveTemplateFromFileStream(fsProps, metadata, ctx, opts...)
 }
 
 // SaveTemplateFromFileStream is a method that allows saving a template from a file stream.
 // It initializes the template creation, uploads the file to the Pogodoc service, extracts the template files,
 // generates previews, and saves the template with the provided metadata.
 // It returns the template ID or an error if any step fails.
-func (c *PogodocClient) SaveTemplateFromFileStream(fsProps FileStreamProps, metadata SaveCreatedTemplateRequestTemplateInfo, ctx context.Context) (string, error) {
-	response, err := c.Templates.InitializeTemplateCreation(ctx)
+func (c *PogodocClient) SaveTemplateFromFileStream(fsProps FileStreamProps, metadata SaveCreatedTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (_ string, err error) {
+	ctx, span := c.startSpan(ctx, "SaveTemplateFromFileStream")
+	defer func() { endSpan(span, err) }()
+
+	if options := newTemplateOptions(opts); options.progress != nil {
+		fsProps = fsProps.WithProgress(options.progress)
+	}
+
+	response, err := c.templates().InitializeTemplateCreation(ctx)
 	if err != nil {
-		return "", fmt.Errorf("initializing template creation: %v", err)
+		return "", &StepError{Step: StepInitializeTemplate, Err: err}
 	}
 	templateId := response.TemplateId
+	span.SetAttributes(attrTemplateId.String(templateId))
 
-	err = UploadToS3WithURL(response.PresignedTemplateUploadUrl, fsProps, "application/zip")
+	err = c.UploadToPresignedURL(ctx, response.PresignedTemplateUploadUrl, fsProps, "application/zip")
 	if err != nil {
-		return "", fmt.Errorf("uploading template: %v", err)
+		return "", &StepError{Step: StepUploadTemplate, Err: err}
 	}
 
-	err = c.Templates.ExtractTemplateFiles(ctx, templateId)
+	err = c.templates().ExtractTemplateFiles(ctx, templateId)
 	if err != nil {
-		return "", fmt.Errorf("extracting template files: %v", err)
+		return "", &StepError{Step: StepExtractTemplate, Err: err}
 	}
 	request := GenerateTemplatePreviewsRequest{
 		Type: GenerateTemplatePreviewsRequestType(metadata.Type),
 		Data: metadata.SampleData,
 	}
 
-	previewResponse, err := c.Templates.GenerateTemplatePreviews(ctx, templateId, &request)
+	previewResponse, err := c.templates().GenerateTemplatePreviews(ctx, templateId, &request)
 	if err != nil {
-		return "", fmt.Errorf("generating template previews: %v", err)
+		return "", &StepError{Step: StepGeneratePreviews, Err: err}
 
 	}
 	previewPng := previewResponse.PngPreview.JobId
@@ -127,30 +145,25 @@ func (c *PogodocClient) SaveTemplateFromFileStream(fsProps FileStreamProps, meta
 		},
 	}
 
-	err = c.Templates.SaveCreatedTemplate(ctx, templateId, &saveCreatedTemplateRequest)
+	err = c.templates().SaveCreatedTemplate(ctx, templateId, &saveCreatedTemplateRequest)
 	if err != nil {
-		return "", fmt.Errorf("saving created template: %v", err)
+		return "", &StepError{Step: StepSaveTemplate, Err: err}
 	}
+	c.logger().InfoContext(ctx, "saved template", slog.String("template_id", templateId))
 
 	return templateId, nil
 
 }
 
 // UpdateTemplate is a method extension to UpdateTemplateFromFileStream to update an existing template directly from a file path.
-// It wraps the UpdateTemplateFromFileStream method.
-func (c *PogodocClient) UpdateTemplate(templateId string, filePath string, metadata UpdateTemplateRequestTemplateInfo, ctx context.Context) (string, error) {
-	payload, err := ReadFile(filePath)
+// It wraps the UpdateTemplateFromFileStream method, streaming the file from disk.
+func (c *PogodocClient) UpdateTemplate(templateId string, filePath string, metadata UpdateTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (string, error) {
+	fsProps, err := FileStreamFromFile(filePath)
 	if err != nil {
-		return "", fmt.Errorf("file is empty: %v", err)
-	}
-
-	payloadLength := len(payload)
-	fsProps := FileStreamProps{
-		payload:       payload,
-		payloadLength: payloadLength,
+		return "", err
 	}
 
-	return c.UpdateTemplateFromFileStream(templateId, fsProps, metadata, ctx)
+	return c.UpdateTemplateFromFileStream(templateId, fsProps, metadata, ctx, opts...)
 
 }
 
@@ -158,30 +171,37 @@ func (c *PogodocClient) UpdateTemplate(templateId string, filePath string, metad
 // It initializes the template creation, uploads the file to the Pogodoc service, extracts the template files,
 // generates previews, and updates the template with the provided metadata.
 // It returns the template ID or an error if any step fails.
-func (c *PogodocClient) UpdateTemplateFromFileStream(templateId string, fsProps FileStreamProps, metadata UpdateTemplateRequestTemplateInfo, ctx context.Context) (string, error) {
-	response, err := c.Templates.InitializeTemplateCreation(ctx)
+func (c *PogodocClient) UpdateTemplateFromFileStream(templateId string, fsProps FileStreamProps, metadata UpdateTemplateRequestTemplateInfo, ctx context.Context, opts ...TemplateOption) (_ string, err error) {
+	ctx, span := c.startSpan(ctx, "UpdateTemplateFromFileStream", attrTemplateId.String(templateId))
+	defer func() { endSpan(span, err) }()
+
+	if options := newTemplateOptions(opts); options.progress != nil {
+		fsProps = fsProps.WithProgress(options.progress)
+	}
+
+	response, err := c.templates().InitializeTemplateCreation(ctx)
 	if err != nil {
-		return "", fmt.Errorf("initializing template creation: %v", err)
+		return "", &StepError{Step: StepInitializeTemplate, Err: err}
 	}
 	contentId := response.TemplateId
 
-	err = UploadToS3WithURL(response.PresignedTemplateUploadUrl, fsProps, "application/zip")
+	err = c.UploadToPresignedURL(ctx, response.PresignedTemplateUploadUrl, fsProps, "application/zip")
 	if err != nil {
-		return "", fmt.Errorf("uploading template: %v", err)
+		return "", &StepError{Step: StepUploadTemplate, Err: err}
 	}
 
-	err = c.Templates.ExtractTemplateFiles(ctx, contentId)
+	err = c.templates().ExtractTemplateFiles(ctx, contentId)
 	if err != nil {
-		return "", fmt.Errorf("extracting template files: %v", err)
+		return "", &StepError{Step: StepExtractTemplate, Err: err}
 	}
 
 	request := GenerateTemplatePreviewsRequest{
 		Type: GenerateTemplatePreviewsRequestType(metadata.Type),
 		Data: metadata.SampleData,
 	}
-	previewResponse, err := c.Templates.GenerateTemplatePreviews(ctx, contentId, &request)
+	previewResponse, err := c.templates().GenerateTemplatePreviews(ctx, contentId, &request)
 	if err != nil {
-		return "", fmt.Errorf("generating template previews: %v", err)
+		return "", &StepError{Step: StepGeneratePreviews, Err: err}
 	}
 
 	updateTemplateReq := &UpdateTemplateRequest{
@@ -200,10 +220,11 @@ func (c *PogodocClient) UpdateTemplateFromFileStream(templateId string, fsProps
 		ContentId: contentId,
 	}
 
-	_, err = c.Templates.UpdateTemplate(ctx, templateId, updateTemplateReq)
+	_, err = c.templates().UpdateTemplate(ctx, templateId, updateTemplateReq)
 	if err != nil {
-		return "", fmt.Errorf("updating template: %v", err)
+		return "", &StepError{Step: StepUpdateTemplate, Err: err}
 	}
+	c.logger().InfoContext(ctx, "updated template", slog.String("template_id", templateId))
 
 	return templateId, nil
 
@@ -213,48 +234,78 @@ func (c *PogodocClient) UpdateTemplateFromFileStream(templateId string, fsProps
 // This is a lower-level method that only initializes the job.
 // You can use this if you want to implement your own polling logic.
 // It returns the job ID.
-// Use PollForJobCompletion with the job ID to get the final result.
+// Use PollForJobCompletion with the job ID to get the final result,
+// or StartGenerateDocumentJob to get a *RenderJob handle instead.
 // You must provide either a templateId of a saved template or a template string in GenerateDocumentProps.
-func (c *PogodocClient) StartGenerateDocument(gdProps GenerateDocumentProps, ctx context.Context) (*string, error) {
+func (c *PogodocClient) StartGenerateDocument(gdProps GenerateDocumentProps, ctx context.Context) (_ *string, err error) {
+	ctx, span := c.startSpan(ctx, "StartGenerateDocument", renderRequestAttributes(gdProps)...)
+	defer func() { endSpan(span, err) }()
+
+	data := gdProps.Data
+	if data == nil {
+		data = gdProps.InitializeRenderJobRequest.Data
+	}
+	payload, contentType, err := encodeData(gdProps.DataEncoder, data)
+	if err != nil {
+		return nil, err
+	}
 
 	initRequest := gdProps.InitializeRenderJobRequest
-	initResponse, err := c.Documents.InitializeRenderJob(ctx, &initRequest)
+	initResponse, err := c.documents().InitializeRenderJob(ctx, &initRequest)
 	if err != nil {
-		return nil, fmt.Errorf("initializing document render: %v", err)
+		return nil, &StepError{Step: StepInitializeRender, Err: err}
+	}
+	if initResponse == nil {
+		return nil, &StepError{Step: StepInitializeRender, Err: errors.New("empty response")}
 	}
+	span.SetAttributes(attrJobId.String(initResponse.JobId))
 
-	Data := []byte(fmt.Sprint(gdProps.InitializeRenderJobRequest.Data))
+	dataURL := initResponse.PresignedDataUploadUrl
+	var templateURL *string
+	if gdProps.Template != nil {
+		templateURL = initResponse.PresignedTemplateUploadUrl
+	}
 
-	if initResponse != nil && initResponse.PresignedDataUploadUrl != nil {
-		err = UploadToS3WithURL(*initResponse.PresignedDataUploadUrl, FileStreamProps{
-			payload:       Data,
-			payloadLength: len(Data),
-		}, "application/json")
+	var dataStream, templateStream FileStreamProps
+	if dataURL != nil {
+		dataStream = payload
+	}
+	if templateURL != nil {
+		templateStream = FileStreamFromBytes([]byte(*gdProps.Template))
+	}
+	if progress := gdProps.UploadProgress; progress != nil {
+		total := dataStream.Size() + templateStream.Size()
+		dataStream = dataStream.WithProgress(func(bytesSent, _ int64) {
+			progress(bytesSent, total)
+		})
+		templateStream = templateStream.WithProgress(func(bytesSent, _ int64) {
+			progress(dataStream.Size()+bytesSent, total)
+		})
+	}
+
+	if dataURL != nil {
+		err = c.UploadToPresignedURL(ctx, *dataURL, dataStream, contentType)
 		if err != nil {
-			return nil, fmt.Errorf("uploading document: %v", err)
+			return nil, &StepError{Step: StepUploadData, Err: err}
 		}
 	}
 
-	template := gdProps.Template
-
-	if template != nil && initResponse.PresignedTemplateUploadUrl != nil {
-		err = UploadToS3WithURL(*initResponse.PresignedTemplateUploadUrl, FileStreamProps{
-			payload:       []byte(*template),
-			payloadLength: len(*template),
-		}, "text/html")
+	if templateURL != nil {
+		err = c.UploadToPresignedURL(ctx, *templateURL, templateStream, "text/html")
 		if err != nil {
-			return nil, fmt.Errorf("uploading document: %v", err)
+			return nil, &StepError{Step: StepUploadHTML, Err: err}
 		}
 	}
 
-	result, err := c.Documents.StartRenderJob(
+	result, err := c.documents().StartRenderJob(
 		ctx,
 		initResponse.JobId,
 		&gdProps.StartRenderJobRequest,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("starting render: %v", err)
+		return nil, &StepError{Step: StepStartRender, Err: err}
 	}
+	c.logger().InfoContext(ctx, "started render job", slog.String("job_id", result.JobId), slog.String("target", string(initRequest.Target)))
 
 	return &result.JobId, nil
 
@@ -264,13 +315,22 @@ func (c *PogodocClient) StartGenerateDocument(gdProps GenerateDocumentProps, ctx
 // This is the recommended method for most use cases, especially for larger documents.
 // It first calls StartGenerateDocument to begin the process, then PollForJobCompletion to wait for the result.
 // You must provide either a templateId of a saved template or a template string in GenerateDocumentProps.
-func (c *PogodocClient) GenerateDocument(gdProps GenerateDocumentProps, ctx context.Context) (*GetJobStatusResponse, error) {
+// An optional PollOptions value is passed through to PollForJobCompletion.
+func (c *PogodocClient) GenerateDocument(gdProps GenerateDocumentProps, ctx context.Context, opts ...PollOptions) (_ *GetJobStatusResponse, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDocument", renderRequestAttributes(gdProps)...)
+	defer func() { endSpan(span, err) }()
+
+	// Fail before starting a render job that could not be polled.
+	if _, err := resolvePollOptions(opts); err != nil {
+		return nil, err
+	}
+
 	jobId, err := c.StartGenerateDocument(gdProps, ctx)
 	if err != nil {
-		return nil, fmt.Errorf("starting document generation: %v", err)
+		return nil, fmt.Errorf("starting document generation: %w", err)
 	}
 
-	return c.PollForJobCompletion(*jobId, ctx)
+	return c.PollForJobCompletion(*jobId, ctx, opts...)
 }
 
 // GenerateDocumentImmediate generates a document and returns the result immediately.
@@ -278,37 +338,41 @@ func (c *PogodocClient) GenerateDocument(gdProps GenerateDocumentProps, ctx cont
 // The result is returned directly in the response.
 // For larger documents or when you need to handle rendering asynchronously, use GenerateDocument.
 // You must provide either a templateId of a saved template or a template string in GenerateDocumentProps.
-func (c *PogodocClient) GenerateDocumentImmediate(gdProps GenerateDocumentProps, ctx context.Context) (*StartImmediateRenderResponse, error) {
+// The data is sent inline, so it must encode to a JSON object; other data is rejected with an error.
+func (c *PogodocClient) GenerateDocumentImmediate(gdProps GenerateDocumentProps, ctx context.Context) (_ *StartImmediateRenderResponse, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDocumentImmediate", renderRequestAttributes(gdProps)...)
+	defer func() { endSpan(span, err) }()
+
+	data := gdProps.InitializeRenderJobRequest.Data
+	if gdProps.Data != nil {
+		dataMap, err := encodeDataMap(gdProps.DataEncoder, gdProps.Data)
+		if err != nil {
+			return nil, err
+		}
+		data = dataMap
+	}
 
-	return c.Documents.StartImmediateRender(ctx, &StartImmediateRenderRequest{
+	return c.documents().StartImmediateRender(ctx, &StartImmediateRenderRequest{
 		Template:   gdProps.Template,
 		TemplateId: gdProps.InitializeRenderJobRequest.TemplateId,
-		Data:       gdProps.InitializeRenderJobRequest.Data,
+		Data:       data,
 		Type:       StartImmediateRenderRequestType(gdProps.InitializeRenderJobRequest.Type),
 		Target:     StartImmediateRenderRequestTarget(gdProps.InitializeRenderJobRequest.Target),
 	})
 }
 
 // PollForJobCompletion polls for the completion of a rendering job.
-// This method repeatedly checks the status of a job until it is 'done'.
-// It will attempt to get the status up to 60 times with a 500ms interval.
-func (c *PogodocClient) PollForJobCompletion(jobId string, ctx context.Context) (*GetJobStatusResponse, error) {
-	maxAttempts := 60
-	intervalMs := 500
-
-	time.Sleep(1 * time.Second)
-
-	for range maxAttempts {
-		jobStatus, err := c.Documents.GetJobStatus(ctx, jobId)
-		if err != nil {
-			return nil, fmt.Errorf("getting job status: %v", err)
-		}
-
-		if jobStatus.Status == "done" {
-			return jobStatus, nil
-		}
-		time.Sleep(time.Duration(intervalMs) * time.Millisecond)
+// This method repeatedly checks the status of a job until it reaches a terminal state.
+// A failed job is reported immediately as a *RenderJobError alongside its status.
+// By default it waits 1s and then checks the status up to 60 times with a 500ms interval;
+// pass a PollOptions value to change the delays, backoff strategy and limits.
+// Passing more than one PollOptions value is an error.
+// Polling stops as soon as ctx is done. If the job does not complete in time,
+// the returned error is a *JobTimeoutError matching ErrJobTimeout.
+func (c *PogodocClient) PollForJobCompletion(jobId string, ctx context.Context, opts ...PollOptions) (*GetJobStatusResponse, error) {
+	pollOpts, err := resolvePollOptions(opts)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, fmt.Errorf("job %s not found", jobId)
+	return c.pollJob(ctx, jobId, pollOpts, nil)
 }
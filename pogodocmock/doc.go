@@ -0,0 +1,11 @@
+// Package pogodocmock provides testify mocks of pogodoc.TemplatesAPI and
+// pogodoc.DocumentsAPI, to unit test code using a PogodocClient built with
+// pogodoc.NewPogodocClient:
+//
+//	documents := pogodocmock.NewDocumentsAPI(t)
+//	documents.EXPECT().GetJobStatus(mock.Anything, "job").Return(jobStatus, nil)
+//	client := pogodoc.NewPogodocClient(pogodocmock.NewTemplatesAPI(t), documents)
+//
+// The mocks are generated by mockery; run go generate at the root of the
+// module after changing the interfaces.
+package pogodocmock
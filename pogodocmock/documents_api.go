@@ -0,0 +1,334 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package pogodocmock
+
+import (
+	context "context"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DocumentsAPI is an autogenerated mock type for the DocumentsAPI type
+type DocumentsAPI struct {
+	mock.Mock
+}
+
+type DocumentsAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *DocumentsAPI) EXPECT() *DocumentsAPI_Expecter {
+	return &DocumentsAPI_Expecter{mock: &_m.Mock}
+}
+
+// GetJobStatus provides a mock function with given fields: ctx, jobId, opts
+func (_m *DocumentsAPI) GetJobStatus(ctx context.Context, jobId string, opts ...pogodoc.RequestOption) (*pogodoc.GetJobStatusResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, jobId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobStatus")
+	}
+
+	var r0 *pogodoc.GetJobStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GetJobStatusResponse, error)); ok {
+		return rf(ctx, jobId, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) *pogodoc.GetJobStatusResponse); ok {
+		r0 = rf(ctx, jobId, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.GetJobStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, jobId, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DocumentsAPI_GetJobStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobStatus'
+type DocumentsAPI_GetJobStatus_Call struct {
+	*mock.Call
+}
+
+// GetJobStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobId string
+//   - opts ...pogodoc.RequestOption
+func (_e *DocumentsAPI_Expecter) GetJobStatus(ctx interface{}, jobId interface{}, opts ...interface{}) *DocumentsAPI_GetJobStatus_Call {
+	return &DocumentsAPI_GetJobStatus_Call{Call: _e.mock.On("GetJobStatus",
+		append([]interface{}{ctx, jobId}, opts...)...)}
+}
+
+func (_c *DocumentsAPI_GetJobStatus_Call) Run(run func(ctx context.Context, jobId string, opts ...pogodoc.RequestOption)) *DocumentsAPI_GetJobStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DocumentsAPI_GetJobStatus_Call) Return(_a0 *pogodoc.GetJobStatusResponse, _a1 error) *DocumentsAPI_GetJobStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DocumentsAPI_GetJobStatus_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GetJobStatusResponse, error)) *DocumentsAPI_GetJobStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InitializeRenderJob provides a mock function with given fields: ctx, request, opts
+func (_m *DocumentsAPI) InitializeRenderJob(ctx context.Context, request *pogodoc.InitializeRenderJobRequest, opts ...pogodoc.RequestOption) (*pogodoc.InitializeRenderJobResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitializeRenderJob")
+	}
+
+	var r0 *pogodoc.InitializeRenderJobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *pogodoc.InitializeRenderJobRequest, ...pogodoc.RequestOption) (*pogodoc.InitializeRenderJobResponse, error)); ok {
+		return rf(ctx, request, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *pogodoc.InitializeRenderJobRequest, ...pogodoc.RequestOption) *pogodoc.InitializeRenderJobResponse); ok {
+		r0 = rf(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.InitializeRenderJobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *pogodoc.InitializeRenderJobRequest, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DocumentsAPI_InitializeRenderJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitializeRenderJob'
+type DocumentsAPI_InitializeRenderJob_Call struct {
+	*mock.Call
+}
+
+// InitializeRenderJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *pogodoc.InitializeRenderJobRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *DocumentsAPI_Expecter) InitializeRenderJob(ctx interface{}, request interface{}, opts ...interface{}) *DocumentsAPI_InitializeRenderJob_Call {
+	return &DocumentsAPI_InitializeRenderJob_Call{Call: _e.mock.On("InitializeRenderJob",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *DocumentsAPI_InitializeRenderJob_Call) Run(run func(ctx context.Context, request *pogodoc.InitializeRenderJobRequest, opts ...pogodoc.RequestOption)) *DocumentsAPI_InitializeRenderJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*pogodoc.InitializeRenderJobRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DocumentsAPI_InitializeRenderJob_Call) Return(_a0 *pogodoc.InitializeRenderJobResponse, _a1 error) *DocumentsAPI_InitializeRenderJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DocumentsAPI_InitializeRenderJob_Call) RunAndReturn(run func(context.Context, *pogodoc.InitializeRenderJobRequest, ...pogodoc.RequestOption) (*pogodoc.InitializeRenderJobResponse, error)) *DocumentsAPI_InitializeRenderJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartImmediateRender provides a mock function with given fields: ctx, request, opts
+func (_m *DocumentsAPI) StartImmediateRender(ctx context.Context, request *pogodoc.StartImmediateRenderRequest, opts ...pogodoc.RequestOption) (*pogodoc.StartImmediateRenderResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartImmediateRender")
+	}
+
+	var r0 *pogodoc.StartImmediateRenderResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *pogodoc.StartImmediateRenderRequest, ...pogodoc.RequestOption) (*pogodoc.StartImmediateRenderResponse, error)); ok {
+		return rf(ctx, request, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *pogodoc.StartImmediateRenderRequest, ...pogodoc.RequestOption) *pogodoc.StartImmediateRenderResponse); ok {
+		r0 = rf(ctx, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.StartImmediateRenderResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *pogodoc.StartImmediateRenderRequest, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DocumentsAPI_StartImmediateRender_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartImmediateRender'
+type DocumentsAPI_StartImmediateRender_Call struct {
+	*mock.Call
+}
+
+// StartImmediateRender is a helper method to define mock.On call
+//   - ctx context.Context
+//   - request *pogodoc.StartImmediateRenderRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *DocumentsAPI_Expecter) StartImmediateRender(ctx interface{}, request interface{}, opts ...interface{}) *DocumentsAPI_StartImmediateRender_Call {
+	return &DocumentsAPI_StartImmediateRender_Call{Call: _e.mock.On("StartImmediateRender",
+		append([]interface{}{ctx, request}, opts...)...)}
+}
+
+func (_c *DocumentsAPI_StartImmediateRender_Call) Run(run func(ctx context.Context, request *pogodoc.StartImmediateRenderRequest, opts ...pogodoc.RequestOption)) *DocumentsAPI_StartImmediateRender_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(*pogodoc.StartImmediateRenderRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DocumentsAPI_StartImmediateRender_Call) Return(_a0 *pogodoc.StartImmediateRenderResponse, _a1 error) *DocumentsAPI_StartImmediateRender_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DocumentsAPI_StartImmediateRender_Call) RunAndReturn(run func(context.Context, *pogodoc.StartImmediateRenderRequest, ...pogodoc.RequestOption) (*pogodoc.StartImmediateRenderResponse, error)) *DocumentsAPI_StartImmediateRender_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StartRenderJob provides a mock function with given fields: ctx, jobId, request, opts
+func (_m *DocumentsAPI) StartRenderJob(ctx context.Context, jobId string, request *pogodoc.StartRenderJobRequest, opts ...pogodoc.RequestOption) (*pogodoc.StartRenderJobResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, jobId, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartRenderJob")
+	}
+
+	var r0 *pogodoc.StartRenderJobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.StartRenderJobRequest, ...pogodoc.RequestOption) (*pogodoc.StartRenderJobResponse, error)); ok {
+		return rf(ctx, jobId, request, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.StartRenderJobRequest, ...pogodoc.RequestOption) *pogodoc.StartRenderJobResponse); ok {
+		r0 = rf(ctx, jobId, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.StartRenderJobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *pogodoc.StartRenderJobRequest, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, jobId, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DocumentsAPI_StartRenderJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StartRenderJob'
+type DocumentsAPI_StartRenderJob_Call struct {
+	*mock.Call
+}
+
+// StartRenderJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobId string
+//   - request *pogodoc.StartRenderJobRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *DocumentsAPI_Expecter) StartRenderJob(ctx interface{}, jobId interface{}, request interface{}, opts ...interface{}) *DocumentsAPI_StartRenderJob_Call {
+	return &DocumentsAPI_StartRenderJob_Call{Call: _e.mock.On("StartRenderJob",
+		append([]interface{}{ctx, jobId, request}, opts...)...)}
+}
+
+func (_c *DocumentsAPI_StartRenderJob_Call) Run(run func(ctx context.Context, jobId string, request *pogodoc.StartRenderJobRequest, opts ...pogodoc.RequestOption)) *DocumentsAPI_StartRenderJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(*pogodoc.StartRenderJobRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DocumentsAPI_StartRenderJob_Call) Return(_a0 *pogodoc.StartRenderJobResponse, _a1 error) *DocumentsAPI_StartRenderJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DocumentsAPI_StartRenderJob_Call) RunAndReturn(run func(context.Context, string, *pogodoc.StartRenderJobRequest, ...pogodoc.RequestOption) (*pogodoc.StartRenderJobResponse, error)) *DocumentsAPI_StartRenderJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewDocumentsAPI creates a new instance of DocumentsAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDocumentsAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DocumentsAPI {
+	mock := &DocumentsAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
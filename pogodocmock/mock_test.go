@@ -0,0 +1,79 @@
+package pogodocmock_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	"github.com/Pogodoc/pogodoc-go/client/core"
+	"github.com/Pogodoc/pogodoc-go/pogodocmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPogodocClient(t *testing.T) {
+	var uploaded []byte
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploaded, _ = io.ReadAll(r.Body)
+	}))
+	defer storage.Close()
+
+	templates := pogodocmock.NewTemplatesAPI(t)
+	documents := pogodocmock.NewDocumentsAPI(t)
+	client := pogodoc.NewPogodocClient(templates, documents)
+	ctx := context.Background()
+
+	documents.EXPECT().
+		InitializeRenderJob(mock.Anything, mock.MatchedBy(func(request *pogodoc.InitializeRenderJobRequest) bool {
+			return *request.TemplateId == "template"
+		})).
+		Return(&pogodoc.InitializeRenderJobResponse{JobId: "job", PresignedDataUploadUrl: pogodoc.String(storage.URL)}, nil)
+	documents.EXPECT().
+		StartRenderJob(mock.Anything, "job", mock.Anything).
+		Return(&pogodoc.StartRenderJobResponse{JobId: "job"}, nil)
+	documents.EXPECT().
+		GetJobStatus(mock.Anything, "job").
+		Return(&pogodoc.GetJobStatusResponse{JobId: "job", Status: "done"}, nil)
+
+	jobStatus, err := client.GenerateDocument(pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:       pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target:     pogodoc.InitializeRenderJobRequestTargetPdf,
+			TemplateId: pogodoc.String("template"),
+		},
+		Data: map[string]string{"name": "Pogodoc"},
+	}, ctx, pogodoc.PollOptions{InitialDelay: -1})
+	require.NoError(t, err)
+	assert.Equal(t, "done", jobStatus.Status)
+	assert.JSONEq(t, `{"name":"Pogodoc"}`, string(uploaded))
+
+	templates.EXPECT().
+		GetTemplateIndexHtml(mock.Anything, "missing").
+		Return(nil, core.NewAPIError(http.StatusNotFound, nil))
+
+	_, err = client.Templates.GetTemplateIndexHtml(ctx, "missing")
+	assert.ErrorIs(t, err, pogodoc.ErrNotFound)
+}
+
+func TestNewPogodocClientWithEmptyResponse(t *testing.T) {
+	documents := pogodocmock.NewDocumentsAPI(t)
+	client := pogodoc.NewPogodocClient(pogodocmock.NewTemplatesAPI(t), documents)
+
+	documents.EXPECT().
+		InitializeRenderJob(mock.Anything, mock.Anything).
+		Return(nil, nil)
+
+	_, err := client.StartGenerateDocument(pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:   pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target: pogodoc.InitializeRenderJobRequestTargetPdf,
+		},
+	}, context.Background())
+	var stepErr *pogodoc.StepError
+	require.ErrorAs(t, err, &stepErr)
+	assert.Equal(t, pogodoc.StepInitializeRender, stepErr.Step)
+}
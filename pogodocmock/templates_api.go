@@ -0,0 +1,744 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package pogodocmock
+
+import (
+	context "context"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TemplatesAPI is an autogenerated mock type for the TemplatesAPI type
+type TemplatesAPI struct {
+	mock.Mock
+}
+
+type TemplatesAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TemplatesAPI) EXPECT() *TemplatesAPI_Expecter {
+	return &TemplatesAPI_Expecter{mock: &_m.Mock}
+}
+
+// CloneTemplate provides a mock function with given fields: ctx, templateId, opts
+func (_m *TemplatesAPI) CloneTemplate(ctx context.Context, templateId string, opts ...pogodoc.RequestOption) (*pogodoc.CloneTemplateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloneTemplate")
+	}
+
+	var r0 *pogodoc.CloneTemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.CloneTemplateResponse, error)); ok {
+		return rf(ctx, templateId, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) *pogodoc.CloneTemplateResponse); ok {
+		r0 = rf(ctx, templateId, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.CloneTemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_CloneTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloneTemplate'
+type TemplatesAPI_CloneTemplate_Call struct {
+	*mock.Call
+}
+
+// CloneTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) CloneTemplate(ctx interface{}, templateId interface{}, opts ...interface{}) *TemplatesAPI_CloneTemplate_Call {
+	return &TemplatesAPI_CloneTemplate_Call{Call: _e.mock.On("CloneTemplate",
+		append([]interface{}{ctx, templateId}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_CloneTemplate_Call) Run(run func(ctx context.Context, templateId string, opts ...pogodoc.RequestOption)) *TemplatesAPI_CloneTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_CloneTemplate_Call) Return(_a0 *pogodoc.CloneTemplateResponse, _a1 error) *TemplatesAPI_CloneTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_CloneTemplate_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.CloneTemplateResponse, error)) *TemplatesAPI_CloneTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTemplate provides a mock function with given fields: ctx, templateId, opts
+func (_m *TemplatesAPI) DeleteTemplate(ctx context.Context, templateId string, opts ...pogodoc.RequestOption) (*pogodoc.DeleteTemplateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTemplate")
+	}
+
+	var r0 *pogodoc.DeleteTemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.DeleteTemplateResponse, error)); ok {
+		return rf(ctx, templateId, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) *pogodoc.DeleteTemplateResponse); ok {
+		r0 = rf(ctx, templateId, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.DeleteTemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_DeleteTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTemplate'
+type TemplatesAPI_DeleteTemplate_Call struct {
+	*mock.Call
+}
+
+// DeleteTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) DeleteTemplate(ctx interface{}, templateId interface{}, opts ...interface{}) *TemplatesAPI_DeleteTemplate_Call {
+	return &TemplatesAPI_DeleteTemplate_Call{Call: _e.mock.On("DeleteTemplate",
+		append([]interface{}{ctx, templateId}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_DeleteTemplate_Call) Run(run func(ctx context.Context, templateId string, opts ...pogodoc.RequestOption)) *TemplatesAPI_DeleteTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_DeleteTemplate_Call) Return(_a0 *pogodoc.DeleteTemplateResponse, _a1 error) *TemplatesAPI_DeleteTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_DeleteTemplate_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.DeleteTemplateResponse, error)) *TemplatesAPI_DeleteTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExtractTemplateFiles provides a mock function with given fields: ctx, templateId, opts
+func (_m *TemplatesAPI) ExtractTemplateFiles(ctx context.Context, templateId string, opts ...pogodoc.RequestOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtractTemplateFiles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r0 = rf(ctx, templateId, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesAPI_ExtractTemplateFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExtractTemplateFiles'
+type TemplatesAPI_ExtractTemplateFiles_Call struct {
+	*mock.Call
+}
+
+// ExtractTemplateFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) ExtractTemplateFiles(ctx interface{}, templateId interface{}, opts ...interface{}) *TemplatesAPI_ExtractTemplateFiles_Call {
+	return &TemplatesAPI_ExtractTemplateFiles_Call{Call: _e.mock.On("ExtractTemplateFiles",
+		append([]interface{}{ctx, templateId}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_ExtractTemplateFiles_Call) Run(run func(ctx context.Context, templateId string, opts ...pogodoc.RequestOption)) *TemplatesAPI_ExtractTemplateFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_ExtractTemplateFiles_Call) Return(_a0 error) *TemplatesAPI_ExtractTemplateFiles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesAPI_ExtractTemplateFiles_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) error) *TemplatesAPI_ExtractTemplateFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GeneratePresignedGetUrl provides a mock function with given fields: ctx, templateId, opts
+func (_m *TemplatesAPI) GeneratePresignedGetUrl(ctx context.Context, templateId string, opts ...pogodoc.RequestOption) (*pogodoc.GeneratePresignedGetUrlResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeneratePresignedGetUrl")
+	}
+
+	var r0 *pogodoc.GeneratePresignedGetUrlResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GeneratePresignedGetUrlResponse, error)); ok {
+		return rf(ctx, templateId, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) *pogodoc.GeneratePresignedGetUrlResponse); ok {
+		r0 = rf(ctx, templateId, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.GeneratePresignedGetUrlResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_GeneratePresignedGetUrl_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GeneratePresignedGetUrl'
+type TemplatesAPI_GeneratePresignedGetUrl_Call struct {
+	*mock.Call
+}
+
+// GeneratePresignedGetUrl is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) GeneratePresignedGetUrl(ctx interface{}, templateId interface{}, opts ...interface{}) *TemplatesAPI_GeneratePresignedGetUrl_Call {
+	return &TemplatesAPI_GeneratePresignedGetUrl_Call{Call: _e.mock.On("GeneratePresignedGetUrl",
+		append([]interface{}{ctx, templateId}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_GeneratePresignedGetUrl_Call) Run(run func(ctx context.Context, templateId string, opts ...pogodoc.RequestOption)) *TemplatesAPI_GeneratePresignedGetUrl_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_GeneratePresignedGetUrl_Call) Return(_a0 *pogodoc.GeneratePresignedGetUrlResponse, _a1 error) *TemplatesAPI_GeneratePresignedGetUrl_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_GeneratePresignedGetUrl_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GeneratePresignedGetUrlResponse, error)) *TemplatesAPI_GeneratePresignedGetUrl_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GenerateTemplatePreviews provides a mock function with given fields: ctx, templateId, request, opts
+func (_m *TemplatesAPI) GenerateTemplatePreviews(ctx context.Context, templateId string, request *pogodoc.GenerateTemplatePreviewsRequest, opts ...pogodoc.RequestOption) (*pogodoc.GenerateTemplatePreviewsResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateTemplatePreviews")
+	}
+
+	var r0 *pogodoc.GenerateTemplatePreviewsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.GenerateTemplatePreviewsRequest, ...pogodoc.RequestOption) (*pogodoc.GenerateTemplatePreviewsResponse, error)); ok {
+		return rf(ctx, templateId, request, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.GenerateTemplatePreviewsRequest, ...pogodoc.RequestOption) *pogodoc.GenerateTemplatePreviewsResponse); ok {
+		r0 = rf(ctx, templateId, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.GenerateTemplatePreviewsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *pogodoc.GenerateTemplatePreviewsRequest, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_GenerateTemplatePreviews_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateTemplatePreviews'
+type TemplatesAPI_GenerateTemplatePreviews_Call struct {
+	*mock.Call
+}
+
+// GenerateTemplatePreviews is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - request *pogodoc.GenerateTemplatePreviewsRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) GenerateTemplatePreviews(ctx interface{}, templateId interface{}, request interface{}, opts ...interface{}) *TemplatesAPI_GenerateTemplatePreviews_Call {
+	return &TemplatesAPI_GenerateTemplatePreviews_Call{Call: _e.mock.On("GenerateTemplatePreviews",
+		append([]interface{}{ctx, templateId, request}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_GenerateTemplatePreviews_Call) Run(run func(ctx context.Context, templateId string, request *pogodoc.GenerateTemplatePreviewsRequest, opts ...pogodoc.RequestOption)) *TemplatesAPI_GenerateTemplatePreviews_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(*pogodoc.GenerateTemplatePreviewsRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_GenerateTemplatePreviews_Call) Return(_a0 *pogodoc.GenerateTemplatePreviewsResponse, _a1 error) *TemplatesAPI_GenerateTemplatePreviews_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_GenerateTemplatePreviews_Call) RunAndReturn(run func(context.Context, string, *pogodoc.GenerateTemplatePreviewsRequest, ...pogodoc.RequestOption) (*pogodoc.GenerateTemplatePreviewsResponse, error)) *TemplatesAPI_GenerateTemplatePreviews_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTemplateIndexHtml provides a mock function with given fields: ctx, templateId, opts
+func (_m *TemplatesAPI) GetTemplateIndexHtml(ctx context.Context, templateId string, opts ...pogodoc.RequestOption) (*pogodoc.GetTemplateIndexHtmlResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplateIndexHtml")
+	}
+
+	var r0 *pogodoc.GetTemplateIndexHtmlResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GetTemplateIndexHtmlResponse, error)); ok {
+		return rf(ctx, templateId, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...pogodoc.RequestOption) *pogodoc.GetTemplateIndexHtmlResponse); ok {
+		r0 = rf(ctx, templateId, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.GetTemplateIndexHtmlResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_GetTemplateIndexHtml_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTemplateIndexHtml'
+type TemplatesAPI_GetTemplateIndexHtml_Call struct {
+	*mock.Call
+}
+
+// GetTemplateIndexHtml is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) GetTemplateIndexHtml(ctx interface{}, templateId interface{}, opts ...interface{}) *TemplatesAPI_GetTemplateIndexHtml_Call {
+	return &TemplatesAPI_GetTemplateIndexHtml_Call{Call: _e.mock.On("GetTemplateIndexHtml",
+		append([]interface{}{ctx, templateId}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_GetTemplateIndexHtml_Call) Run(run func(ctx context.Context, templateId string, opts ...pogodoc.RequestOption)) *TemplatesAPI_GetTemplateIndexHtml_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_GetTemplateIndexHtml_Call) Return(_a0 *pogodoc.GetTemplateIndexHtmlResponse, _a1 error) *TemplatesAPI_GetTemplateIndexHtml_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_GetTemplateIndexHtml_Call) RunAndReturn(run func(context.Context, string, ...pogodoc.RequestOption) (*pogodoc.GetTemplateIndexHtmlResponse, error)) *TemplatesAPI_GetTemplateIndexHtml_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InitializeTemplateCreation provides a mock function with given fields: ctx, opts
+func (_m *TemplatesAPI) InitializeTemplateCreation(ctx context.Context, opts ...pogodoc.RequestOption) (*pogodoc.InitializeTemplateCreationResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitializeTemplateCreation")
+	}
+
+	var r0 *pogodoc.InitializeTemplateCreationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...pogodoc.RequestOption) (*pogodoc.InitializeTemplateCreationResponse, error)); ok {
+		return rf(ctx, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...pogodoc.RequestOption) *pogodoc.InitializeTemplateCreationResponse); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.InitializeTemplateCreationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_InitializeTemplateCreation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InitializeTemplateCreation'
+type TemplatesAPI_InitializeTemplateCreation_Call struct {
+	*mock.Call
+}
+
+// InitializeTemplateCreation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) InitializeTemplateCreation(ctx interface{}, opts ...interface{}) *TemplatesAPI_InitializeTemplateCreation_Call {
+	return &TemplatesAPI_InitializeTemplateCreation_Call{Call: _e.mock.On("InitializeTemplateCreation",
+		append([]interface{}{ctx}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_InitializeTemplateCreation_Call) Run(run func(ctx context.Context, opts ...pogodoc.RequestOption)) *TemplatesAPI_InitializeTemplateCreation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_InitializeTemplateCreation_Call) Return(_a0 *pogodoc.InitializeTemplateCreationResponse, _a1 error) *TemplatesAPI_InitializeTemplateCreation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_InitializeTemplateCreation_Call) RunAndReturn(run func(context.Context, ...pogodoc.RequestOption) (*pogodoc.InitializeTemplateCreationResponse, error)) *TemplatesAPI_InitializeTemplateCreation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveCreatedTemplate provides a mock function with given fields: ctx, templateId, request, opts
+func (_m *TemplatesAPI) SaveCreatedTemplate(ctx context.Context, templateId string, request *pogodoc.SaveCreatedTemplateRequest, opts ...pogodoc.RequestOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCreatedTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.SaveCreatedTemplateRequest, ...pogodoc.RequestOption) error); ok {
+		r0 = rf(ctx, templateId, request, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesAPI_SaveCreatedTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveCreatedTemplate'
+type TemplatesAPI_SaveCreatedTemplate_Call struct {
+	*mock.Call
+}
+
+// SaveCreatedTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - request *pogodoc.SaveCreatedTemplateRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) SaveCreatedTemplate(ctx interface{}, templateId interface{}, request interface{}, opts ...interface{}) *TemplatesAPI_SaveCreatedTemplate_Call {
+	return &TemplatesAPI_SaveCreatedTemplate_Call{Call: _e.mock.On("SaveCreatedTemplate",
+		append([]interface{}{ctx, templateId, request}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_SaveCreatedTemplate_Call) Run(run func(ctx context.Context, templateId string, request *pogodoc.SaveCreatedTemplateRequest, opts ...pogodoc.RequestOption)) *TemplatesAPI_SaveCreatedTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(*pogodoc.SaveCreatedTemplateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_SaveCreatedTemplate_Call) Return(_a0 error) *TemplatesAPI_SaveCreatedTemplate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesAPI_SaveCreatedTemplate_Call) RunAndReturn(run func(context.Context, string, *pogodoc.SaveCreatedTemplateRequest, ...pogodoc.RequestOption) error) *TemplatesAPI_SaveCreatedTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTemplate provides a mock function with given fields: ctx, templateId, request, opts
+func (_m *TemplatesAPI) UpdateTemplate(ctx context.Context, templateId string, request *pogodoc.UpdateTemplateRequest, opts ...pogodoc.RequestOption) (*pogodoc.UpdateTemplateResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTemplate")
+	}
+
+	var r0 *pogodoc.UpdateTemplateResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.UpdateTemplateRequest, ...pogodoc.RequestOption) (*pogodoc.UpdateTemplateResponse, error)); ok {
+		return rf(ctx, templateId, request, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.UpdateTemplateRequest, ...pogodoc.RequestOption) *pogodoc.UpdateTemplateResponse); ok {
+		r0 = rf(ctx, templateId, request, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pogodoc.UpdateTemplateResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *pogodoc.UpdateTemplateRequest, ...pogodoc.RequestOption) error); ok {
+		r1 = rf(ctx, templateId, request, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TemplatesAPI_UpdateTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTemplate'
+type TemplatesAPI_UpdateTemplate_Call struct {
+	*mock.Call
+}
+
+// UpdateTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - request *pogodoc.UpdateTemplateRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) UpdateTemplate(ctx interface{}, templateId interface{}, request interface{}, opts ...interface{}) *TemplatesAPI_UpdateTemplate_Call {
+	return &TemplatesAPI_UpdateTemplate_Call{Call: _e.mock.On("UpdateTemplate",
+		append([]interface{}{ctx, templateId, request}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_UpdateTemplate_Call) Run(run func(ctx context.Context, templateId string, request *pogodoc.UpdateTemplateRequest, opts ...pogodoc.RequestOption)) *TemplatesAPI_UpdateTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(*pogodoc.UpdateTemplateRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_UpdateTemplate_Call) Return(_a0 *pogodoc.UpdateTemplateResponse, _a1 error) *TemplatesAPI_UpdateTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TemplatesAPI_UpdateTemplate_Call) RunAndReturn(run func(context.Context, string, *pogodoc.UpdateTemplateRequest, ...pogodoc.RequestOption) (*pogodoc.UpdateTemplateResponse, error)) *TemplatesAPI_UpdateTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UploadTemplateIndexHtml provides a mock function with given fields: ctx, templateId, request, opts
+func (_m *TemplatesAPI) UploadTemplateIndexHtml(ctx context.Context, templateId string, request *pogodoc.UploadTemplateIndexHtmlRequest, opts ...pogodoc.RequestOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, templateId, request)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadTemplateIndexHtml")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *pogodoc.UploadTemplateIndexHtmlRequest, ...pogodoc.RequestOption) error); ok {
+		r0 = rf(ctx, templateId, request, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TemplatesAPI_UploadTemplateIndexHtml_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UploadTemplateIndexHtml'
+type TemplatesAPI_UploadTemplateIndexHtml_Call struct {
+	*mock.Call
+}
+
+// UploadTemplateIndexHtml is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateId string
+//   - request *pogodoc.UploadTemplateIndexHtmlRequest
+//   - opts ...pogodoc.RequestOption
+func (_e *TemplatesAPI_Expecter) UploadTemplateIndexHtml(ctx interface{}, templateId interface{}, request interface{}, opts ...interface{}) *TemplatesAPI_UploadTemplateIndexHtml_Call {
+	return &TemplatesAPI_UploadTemplateIndexHtml_Call{Call: _e.mock.On("UploadTemplateIndexHtml",
+		append([]interface{}{ctx, templateId, request}, opts...)...)}
+}
+
+func (_c *TemplatesAPI_UploadTemplateIndexHtml_Call) Run(run func(ctx context.Context, templateId string, request *pogodoc.UploadTemplateIndexHtmlRequest, opts ...pogodoc.RequestOption)) *TemplatesAPI_UploadTemplateIndexHtml_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]pogodoc.RequestOption, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(pogodoc.RequestOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(*pogodoc.UploadTemplateIndexHtmlRequest), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TemplatesAPI_UploadTemplateIndexHtml_Call) Return(_a0 error) *TemplatesAPI_UploadTemplateIndexHtml_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TemplatesAPI_UploadTemplateIndexHtml_Call) RunAndReturn(run func(context.Context, string, *pogodoc.UploadTemplateIndexHtmlRequest, ...pogodoc.RequestOption) error) *TemplatesAPI_UploadTemplateIndexHtml_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTemplatesAPI creates a new instance of TemplatesAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTemplatesAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TemplatesAPI {
+	mock := &TemplatesAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
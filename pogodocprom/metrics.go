@@ -0,0 +1,104 @@
+// Package pogodocprom exports the metrics of the Pogodoc SDK to Prometheus.
+// It is a separate module, so the SDK itself does not depend on Prometheus:
+//
+//	go get github.com/Pogodoc/pogodoc-go/pogodocprom
+//
+//	metrics, err := pogodocprom.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	client, err := pogodoc.PogodocClientInit(pogodoc.WithMetrics(metrics))
+package pogodocprom
+
+import (
+	"strconv"
+	"time"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements pogodoc.Metrics with Prometheus collectors:
+//
+//   - pogodoc_request_duration_seconds, a histogram of request latencies by endpoint and status code;
+//   - pogodoc_retries_total, a counter of retries by status code ("0" for network errors);
+//   - pogodoc_upload_bytes_total, a counter of bytes uploaded to presigned URLs;
+//   - pogodoc_poll_attempts, a histogram of status checks per job by final status;
+//   - pogodoc_render_time_seconds, a histogram of the render times reported by the API by target.
+type Metrics struct {
+	requestDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	uploadBytes     prometheus.Counter
+	pollAttempts    *prometheus.HistogramVec
+	renderTime      *prometheus.HistogramVec
+}
+
+var _ pogodoc.Metrics = (*Metrics)(nil)
+
+// New creates the collectors and registers them with registerer.
+func New(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pogodoc",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of Pogodoc API calls and presigned URL transfers, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "code"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "pogodoc",
+			Name:      "retries_total",
+			Help:      "Retried requests by the status code that caused the retry, 0 for network errors.",
+		}, []string{"code"}),
+		uploadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "pogodoc",
+			Name:      "upload_bytes_total",
+			Help:      "Bytes uploaded to presigned URLs.",
+		}),
+		pollAttempts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pogodoc",
+			Name:      "poll_attempts",
+			Help:      "Status checks made per render job, by the last status seen.",
+			Buckets:   []float64{1, 2, 3, 5, 10, 20, 30, 60, 120},
+		}, []string{"status"}),
+		renderTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "pogodoc",
+			Name:      "render_time_seconds",
+			Help:      "Render time reported by the Pogodoc API for finished jobs.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"target"}),
+	}
+
+	collectors := []prometheus.Collector{m.requestDuration, m.retries, m.uploadBytes, m.pollAttempts, m.renderTime}
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest implements pogodoc.Metrics.
+func (m *Metrics) ObserveRequest(endpoint string, statusCode int, duration time.Duration) {
+	m.requestDuration.WithLabelValues(endpoint, strconv.Itoa(statusCode)).Observe(duration.Seconds())
+}
+
+// IncRetry implements pogodoc.Metrics.
+func (m *Metrics) IncRetry(statusCode int) {
+	m.retries.WithLabelValues(strconv.Itoa(statusCode)).Inc()
+}
+
+// AddUploadBytes implements pogodoc.Metrics.
+func (m *Metrics) AddUploadBytes(bytes int64) {
+	m.uploadBytes.Add(float64(bytes))
+}
+
+// ObservePollAttempts implements pogodoc.Metrics.
+func (m *Metrics) ObservePollAttempts(attempts int, status string) {
+	m.pollAttempts.WithLabelValues(status).Observe(float64(attempts))
+}
+
+// ObserveRenderTime implements pogodoc.Metrics. The render time reported
+// by the API in milliseconds is recorded in seconds.
+func (m *Metrics) ObserveRenderTime(target string, renderTime float64) {
+	m.renderTime.WithLabelValues(target).Observe(renderTime / 1000)
+}
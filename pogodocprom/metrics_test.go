@@ -0,0 +1,81 @@
+package pogodocprom
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := New(registry)
+	require.NoError(t, err)
+
+	metrics.ObserveRequest("documents.GetJobStatus", 200, 50*time.Millisecond)
+	metrics.IncRetry(503)
+	metrics.IncRetry(503)
+	metrics.IncRetry(0)
+	metrics.AddUploadBytes(1024)
+	metrics.ObservePollAttempts(3, "done")
+	metrics.ObserveRenderTime("pdf", 1200)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.retries.WithLabelValues("503")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.retries.WithLabelValues("0")))
+	assert.Equal(t, 1024.0, testutil.ToFloat64(metrics.uploadBytes))
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP pogodoc_poll_attempts Status checks made per render job, by the last status seen.
+# TYPE pogodoc_poll_attempts histogram
+pogodoc_poll_attempts_bucket{status="done",le="1"} 0
+pogodoc_poll_attempts_bucket{status="done",le="2"} 0
+pogodoc_poll_attempts_bucket{status="done",le="3"} 1
+pogodoc_poll_attempts_bucket{status="done",le="5"} 1
+pogodoc_poll_attempts_bucket{status="done",le="10"} 1
+pogodoc_poll_attempts_bucket{status="done",le="20"} 1
+pogodoc_poll_attempts_bucket{status="done",le="30"} 1
+pogodoc_poll_attempts_bucket{status="done",le="60"} 1
+pogodoc_poll_attempts_bucket{status="done",le="120"} 1
+pogodoc_poll_attempts_bucket{status="done",le="+Inf"} 1
+pogodoc_poll_attempts_sum{status="done"} 3
+pogodoc_poll_attempts_count{status="done"} 1
+`), "pogodoc_poll_attempts")
+	assert.NoError(t, err)
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP pogodoc_render_time_seconds Render time reported by the Pogodoc API for finished jobs.
+# TYPE pogodoc_render_time_seconds histogram
+pogodoc_render_time_seconds_bucket{target="pdf",le="0.05"} 0
+pogodoc_render_time_seconds_bucket{target="pdf",le="0.1"} 0
+pogodoc_render_time_seconds_bucket{target="pdf",le="0.2"} 0
+pogodoc_render_time_seconds_bucket{target="pdf",le="0.4"} 0
+pogodoc_render_time_seconds_bucket{target="pdf",le="0.8"} 0
+pogodoc_render_time_seconds_bucket{target="pdf",le="1.6"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="3.2"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="6.4"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="12.8"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="25.6"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="51.2"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="102.4"} 1
+pogodoc_render_time_seconds_bucket{target="pdf",le="+Inf"} 1
+pogodoc_render_time_seconds_sum{target="pdf"} 1.2
+pogodoc_render_time_seconds_count{target="pdf"} 1
+`), "pogodoc_render_time_seconds")
+	assert.NoError(t, err)
+
+	count, err := testutil.GatherAndCount(registry, "pogodoc_request_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestNewRejectsDuplicateRegistration(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := New(registry)
+	require.NoError(t, err)
+	_, err = New(registry)
+	assert.Error(t, err)
+}
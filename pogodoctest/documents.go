@@ -0,0 +1,397 @@
+package pogodoctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	pogodocgoclient "github.com/Pogodoc/pogodoc-go/client"
+)
+
+// Statuses of render jobs.
+const (
+	JobStatusPending    = "pending"
+	JobStatusInProgress = "in-progress"
+	JobStatusDone       = "done"
+	JobStatusFailed     = "failed"
+)
+
+// contentTypes are the content types of the rendered outputs by target.
+var contentTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"html": "text/html",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	"png":  "image/png",
+	"jpg":  "image/jpeg",
+}
+
+// RenderRequest is what a Renderer gets to produce the output of a job.
+type RenderRequest struct {
+	JobId      string
+	TemplateId string
+	Type       string
+	Target     string
+	// Template is the source of the template: the template uploaded for the job,
+	// the template of an immediate render, or the index.html of the template
+	// referenced by TemplateId.
+	Template string
+	// Data is the render data, if it was sent as a JSON object.
+	Data map[string]interface{}
+	// RawData is the render data as uploaded.
+	RawData []byte
+}
+
+// Renderer produces the output of a render. Returning an error fails the render
+// job, with the error as its message.
+type Renderer func(RenderRequest) ([]byte, error)
+
+// DefaultRenderer renders a JSON document describing the render request,
+// so tests can check what the server received.
+func DefaultRenderer(request RenderRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(map[string]interface{}{
+		"templateId": request.TemplateId,
+		"type":       request.Type,
+		"target":     request.Target,
+		"template":   request.Template,
+		"data":       request.Data,
+	})
+	return buf.Bytes(), err
+}
+
+// Job is a render job stored by the server.
+type Job struct {
+	Id         string
+	TemplateId string
+	Type       string
+	Target     string
+	// Status is the status of the job when it was retrieved.
+	Status string
+	// Data is the render data uploaded for the job, or sent when it was initialized.
+	Data []byte
+	// Template is the template uploaded for the job.
+	Template string
+	// Output is the rendered output, once the job is done.
+	Output []byte
+	// Error is the error of a failed job.
+	Error string
+	// UploadPresignedS3Url is the URL passed to StartRenderJob to upload the
+	// output to. It is recorded but not used.
+	UploadPresignedS3Url string
+
+	initData   map[string]interface{}
+	started    bool
+	rendered   bool
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+// status returns the status of the job at the given time.
+func (j *Job) status(now time.Time) string {
+	switch {
+	case !j.started:
+		return JobStatusPending
+	case !j.rendered || now.Before(j.finishedAt):
+		return JobStatusInProgress
+	case j.Error != "":
+		return JobStatusFailed
+	default:
+		return JobStatusDone
+	}
+}
+
+// snapshot returns a copy of the job with its current status.
+func (j *Job) snapshot() Job {
+	snapshot := *j
+	snapshot.Status = j.status(time.Now())
+	return snapshot
+}
+
+// Job returns a copy of the render job with the given ID.
+func (s *Server) Job(jobId string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[jobId]
+	if !ok {
+		return Job{}, false
+	}
+	return job.snapshot(), true
+}
+
+// Jobs returns the number of render jobs stored by the server, including the
+// jobs of template previews and immediate renders.
+func (s *Server) Jobs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+// dataKey is the key of the data uploaded for a job.
+func dataKey(jobId string) string {
+	return "jobs/" + jobId + "/data"
+}
+
+// templateKey is the key of the template uploaded for a job.
+func templateKey(jobId string) string {
+	return "jobs/" + jobId + "/template"
+}
+
+// outputKey is the key of the output of a job.
+func outputKey(job *Job) string {
+	return "jobs/" + job.Id + "/output." + job.Target
+}
+
+// renderJob renders a started job and stores its output. The job finishes
+// once latency has elapsed since it started.
+func (s *Server) renderJob(job *Job, request RenderRequest, latency time.Duration) {
+	if request.Data == nil && len(request.RawData) > 0 {
+		_ = json.Unmarshal(request.RawData, &request.Data)
+	}
+	output, err := s.renderer(request)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.rendered = true
+	job.finishedAt = job.startedAt.Add(latency)
+	if err != nil {
+		job.Error = err.Error()
+		return
+	}
+	job.Output = output
+	s.objects[outputKey(job)] = object{data: output, contentType: contentTypes[job.Target]}
+}
+
+// renderNow renders a job that is done as soon as it is created, as template
+// previews and immediate renders are.
+func (s *Server) renderNow(request RenderRequest) (*Job, error) {
+	s.mu.Lock()
+	job := &Job{
+		Id:         s.newId("job"),
+		TemplateId: request.TemplateId,
+		Type:       request.Type,
+		Target:     request.Target,
+		Data:       request.RawData,
+		started:    true,
+		startedAt:  time.Now(),
+	}
+	s.jobs[job.Id] = job
+	s.mu.Unlock()
+
+	request.JobId = job.Id
+	s.renderJob(job, request, 0)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.Error != "" {
+		return job, errors.New(job.Error)
+	}
+	return job, nil
+}
+
+// jobResponse builds the job status response of the API. The caller must hold s.mu.
+func (s *Server) jobResponse(job *Job) map[string]interface{} {
+	status := job.status(time.Now())
+	response := map[string]interface{}{
+		"jobId":  job.Id,
+		"target": job.Target,
+		"status": status,
+	}
+	if job.TemplateId != "" {
+		response["templateId"] = job.TemplateId
+	}
+	if job.UploadPresignedS3Url != "" {
+		response["uploadPresignedS3Url"] = job.UploadPresignedS3Url
+	}
+	switch status {
+	case JobStatusDone:
+		response["success"] = true
+		response["output"] = map[string]interface{}{
+			"data": map[string]string{
+				"url": s.presignedURL(outputKey(job)),
+			},
+			"metadata": map[string]float64{
+				"renderTime": float64(job.finishedAt.Sub(job.startedAt).Milliseconds()),
+			},
+		}
+	case JobStatusFailed:
+		response["success"] = false
+		response["error"] = job.Error
+	}
+	return response
+}
+
+// renderRequestIssues validates the type and target of a render request.
+func renderRequestIssues(templateType string, target string) []issue {
+	var issues []issue
+	if _, err := pogodocgoclient.NewInitializeRenderJobRequestTypeFromString(templateType); err != nil {
+		issues = append(issues, issue{Path: "type", Message: err.Error()})
+	}
+	if _, err := pogodocgoclient.NewInitializeRenderJobRequestTargetFromString(target); err != nil {
+		issues = append(issues, issue{Path: "target", Message: err.Error()})
+	}
+	return issues
+}
+
+func (s *Server) initializeRenderJob(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.InitializeRenderJobRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if issues := renderRequestIssues(string(request.Type), string(request.Target)); len(issues) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request", issues...)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := &Job{
+		Id:       s.newId("job"),
+		Type:     string(request.Type),
+		Target:   string(request.Target),
+		initData: request.Data,
+	}
+	if request.TemplateId != nil {
+		if _, ok := s.templates[*request.TemplateId]; !ok {
+			writeError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		job.TemplateId = *request.TemplateId
+	}
+	s.jobs[job.Id] = job
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"jobId":                      job.Id,
+		"target":                     job.Target,
+		"presignedDataUploadUrl":     s.presignedURL(dataKey(job.Id)),
+		"presignedTemplateUploadUrl": s.presignedURL(templateKey(job.Id)),
+	})
+}
+
+func (s *Server) startRenderJob(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.StartRenderJobRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	job, ok := s.jobs[r.PathValue("jobId")]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "Render job not found")
+		return
+	}
+	if job.started {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, "Render job has already been started")
+		return
+	}
+
+	renderRequest := RenderRequest{
+		JobId:      job.Id,
+		TemplateId: job.TemplateId,
+		Type:       job.Type,
+		Target:     job.Target,
+	}
+	if template, ok := s.object(templateKey(job.Id)); ok {
+		job.Template = string(template)
+		renderRequest.Template = job.Template
+	} else if template, ok := s.templates[job.TemplateId]; ok {
+		renderRequest.Template = template.IndexHtml
+	} else {
+		s.mu.Unlock()
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request",
+			issue{Path: "templateId", Message: "A template must be uploaded or referenced by ID"})
+		return
+	}
+	if data, ok := s.object(dataKey(job.Id)); ok {
+		job.Data = data
+	} else if job.initData != nil {
+		job.Data, _ = json.Marshal(job.initData)
+	}
+	renderRequest.RawData = job.Data
+	if request.UploadPresignedS3Url != nil {
+		job.UploadPresignedS3Url = *request.UploadPresignedS3Url
+	}
+	job.started = true
+	job.startedAt = time.Now()
+	s.mu.Unlock()
+
+	s.renderJob(job, renderRequest, s.jobLatency)
+
+	if request.ShouldWaitForRenderCompletion != nil && *request.ShouldWaitForRenderCompletion {
+		s.mu.Lock()
+		wait := time.Until(job.finishedAt)
+		s.mu.Unlock()
+		select {
+		case <-time.After(wait):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.jobResponse(job))
+}
+
+func (s *Server) startImmediateRender(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.StartImmediateRenderRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if issues := renderRequestIssues(string(request.Type), string(request.Target)); len(issues) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request", issues...)
+		return
+	}
+
+	renderRequest := RenderRequest{
+		Type:   string(request.Type),
+		Target: string(request.Target),
+		Data:   request.Data,
+	}
+	renderRequest.RawData, _ = json.Marshal(request.Data)
+	s.mu.Lock()
+	switch {
+	case request.Template != nil:
+		renderRequest.Template = *request.Template
+	case request.TemplateId != nil:
+		template, ok := s.templates[*request.TemplateId]
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusNotFound, "Template not found")
+			return
+		}
+		renderRequest.TemplateId = template.Id
+		renderRequest.Template = template.IndexHtml
+	default:
+		s.mu.Unlock()
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request",
+			issue{Path: "template", Message: "Either template or templateId is required"})
+		return
+	}
+	s.mu.Unlock()
+
+	job, err := s.renderNow(renderRequest)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Render failed: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"url": s.presignedURL(outputKey(job))})
+}
+
+func (s *Server) getJobStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[r.PathValue("jobId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Render job not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.jobResponse(job))
+}
@@ -0,0 +1,378 @@
+package pogodoctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Pogodoc/pogodoc-go/internal/redact"
+)
+
+// Mode selects whether a Recorder records or replays HTTP interactions.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails requests that
+	// were not recorded, without touching the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the network and records them, overwriting
+	// the cassette when Save is called.
+	ModeRecord
+)
+
+// ErrNoInteraction is returned by a Recorder in ModeReplay for requests
+// matching none of the recorded interactions left.
+var ErrNoInteraction = errors.New("pogodoctest: no recorded interaction left")
+
+// redacted replaces the credentials scrubbed from recorded interactions.
+const redacted = redact.Placeholder
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request of a recorded Interaction.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response of a recorded Interaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded body. It is stored in the cassette as a string when it
+// is text, and as an object with a base64 field otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) && !bytes.ContainsRune(b, 0) {
+		return marshalJSON(string(b), "")
+	}
+	return marshalJSON(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)}, "")
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var binary struct {
+		Base64 []byte `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &binary); err != nil {
+		return err
+	}
+	*b = binary.Base64
+	return nil
+}
+
+// Scrubber removes sensitive data from an interaction before it is recorded.
+type Scrubber func(*Interaction)
+
+// cassette is the content of a cassette file.
+type cassette struct {
+	Vars         map[string]string `json:"vars,omitempty"`
+	Interactions []Interaction     `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording HTTP interactions to a cassette
+// file and replaying them offline. Install it on a PogodocClient with
+// WithHTTPClient(recorder.Client()) to capture the API calls and the transfers
+// to presigned URLs alike.
+//
+// Tokens and presigned URL signatures are scrubbed before interactions are
+// recorded. Requests are replayed by method, path and body, with JSON bodies
+// compared regardless of formatting and key order; identical requests get the
+// recorded responses in order.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubbers []Scrubber
+
+	mu       sync.Mutex
+	cassette cassette
+	played   []bool
+}
+
+// RecorderOption configures a Recorder.
+type RecorderOption func(*Recorder)
+
+// WithTransport sets the transport requests are recorded from,
+// http.DefaultTransport by default.
+func WithTransport(transport http.RoundTripper) RecorderOption {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithScrubber adds a scrubber, run after the default ones on every
+// interaction before it is recorded.
+func WithScrubber(scrubber Scrubber) RecorderOption {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, scrubber)
+	}
+}
+
+// NewRecorder creates a Recorder for the cassette at path. In ModeReplay,
+// the cassette is loaded and must exist.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrubbers: []Scrubber{scrubCredentials},
+		cassette:  cassette{Vars: make(map[string]string), Interactions: []Interaction{}},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("loading cassette %s: %w", path, err)
+	}
+	r.played = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Client returns an HTTP client sending its requests through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Var records a value the recorded interactions depend on, such as the ID of
+// an existing template, and returns it. In ModeReplay, value is ignored and
+// the recorded value is returned instead.
+func (r *Recorder) Var(name string, value string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode == ModeReplay {
+		return r.cassette.Vars[name]
+	}
+	r.cassette.Vars[name] = value
+	return value
+}
+
+// Save writes the recorded interactions to the cassette file.
+// It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := marshalJSON(r.cassette, "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// marshalJSON encodes v without escaping HTML, which keeps the URLs and
+// markup of cassettes readable, and ends it with a newline.
+func marshalJSON(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unplayed returns the recorded interactions that have not been replayed.
+// After a test, they point to requests the client no longer sends.
+func (r *Recorder) Unplayed() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unplayed []Interaction
+	for i, played := range r.played {
+		if !played {
+			unplayed = append(unplayed, r.cassette.Interactions[i])
+		}
+	}
+	return unplayed
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// record sends req and records the interaction.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   body,
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       respBody,
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// replay returns the response of the first interaction matching req that has
+// not been replayed yet.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	normalized := normalizeBody(scrubBody(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.played[i] || !interaction.matches(req, normalized) {
+			continue
+		}
+		r.played[i] = true
+		response := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+			StatusCode:    response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(response.Body)),
+			ContentLength: int64(len(response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s in %s", ErrNoInteraction, req.Method, req.URL.Path, r.path)
+}
+
+// matches reports whether the interaction was recorded for a request with the
+// method and path of req and the given normalized body.
+func (i Interaction) matches(req *http.Request, normalizedBody string) bool {
+	if i.Request.Method != req.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(i.Request.URL)
+	if err != nil || recordedURL.Path != req.URL.Path {
+		return false
+	}
+	return normalizeBody(i.Request.Body) == normalizedBody
+}
+
+// readRequestBody reads the body of req and replaces it, so it can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// normalizeBody returns a JSON body re-encoded with sorted keys and no
+// insignificant whitespace, and any other body as-is.
+func normalizeBody(body []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return string(body)
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
+
+// scrubCredentials is the default Scrubber. It redacts credentials from the
+// headers, presigned URL signatures from the URL, and both from the bodies.
+func scrubCredentials(interaction *Interaction) {
+	for _, header := range []http.Header{interaction.Request.Header, interaction.Response.Header} {
+		for _, name := range redact.Headers {
+			if header.Get(name) != "" {
+				header.Set(name, redacted)
+			}
+		}
+	}
+	interaction.Request.URL = scrubText(interaction.Request.URL)
+	interaction.Request.Body = scrubBody(interaction.Request.Body)
+	interaction.Response.Body = scrubBody(interaction.Response.Body)
+}
+
+// scrubBody redacts presigned URL signatures from a text body.
+func scrubBody(body []byte) []byte {
+	if !utf8.Valid(body) {
+		return body
+	}
+	return []byte(scrubText(string(body)))
+}
+
+// scrubText redacts the values of sensitive query parameters in text.
+func scrubText(text string) string {
+	return redact.QueryParameterPattern.ReplaceAllString(text, "${1}"+redacted)
+}
@@ -0,0 +1,122 @@
+package pogodoctest_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	"github.com/Pogodoc/pogodoc-go/pogodoctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	gdProps := pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:   pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target: pogodoc.InitializeRenderJobRequestTargetPdf,
+		},
+		Template: pogodoc.String("<h1>Hello</h1>"),
+		Data:     map[string]interface{}{"name": "Pogodoc", "id": 1},
+	}
+	generate := func(recorder *pogodoctest.Recorder, baseURL string) (*pogodoc.GetJobStatusResponse, []byte, error) {
+		client, err := pogodoc.PogodocClientInitWithConfig(baseURL, "secret-token",
+			pogodoc.WithHTTPClient(recorder.Client()), pogodoc.WithRetryBaseDelay(time.Millisecond))
+		require.NoError(t, err)
+		var output bytes.Buffer
+		jobStatus, err := client.GenerateDocumentToWriter(gdProps, &output, context.Background(), fastPolling)
+		return jobStatus, output.Bytes(), err
+	}
+
+	server := pogodoctest.NewServer(pogodoctest.WithJobLatency(20 * time.Millisecond))
+	recorder, err := pogodoctest.NewRecorder(path, pogodoctest.ModeRecord)
+	require.NoError(t, err)
+	baseURL := recorder.Var("baseURL", server.URL)
+	recorded, recordedOutput, err := generate(recorder, baseURL)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+	server.Close()
+
+	cassette, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(cassette), "secret-token")
+	assert.NotContains(t, string(cassette), "Signature=pogodoctest")
+	assert.Contains(t, string(cassette), "X-Amz-Signature=REDACTED")
+
+	t.Run("replay", func(t *testing.T) {
+		recorder, err := pogodoctest.NewRecorder(path, pogodoctest.ModeReplay)
+		require.NoError(t, err)
+		assert.Equal(t, baseURL, recorder.Var("baseURL", "ignored"))
+
+		replayed, output, err := generate(recorder, baseURL)
+		require.NoError(t, err)
+		assert.Equal(t, recorded.JobId, replayed.JobId)
+		assert.Equal(t, recorded.Status, replayed.Status)
+		assert.Equal(t, recorded.Output.Metadata, replayed.Output.Metadata)
+		assert.Equal(t, recordedOutput, output)
+		assert.Empty(t, recorder.Unplayed())
+	})
+
+	t.Run("json bodies are normalized", func(t *testing.T) {
+		recorder, err := pogodoctest.NewRecorder(path, pogodoctest.ModeReplay)
+		require.NoError(t, err)
+
+		body := `{ "target": "pdf", "type": "html" }`
+		resp, err := recorder.Client().Post(baseURL+"/documents/init", "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, recorder.Unplayed())
+	})
+
+	t.Run("unrecorded request", func(t *testing.T) {
+		recorder, err := pogodoctest.NewRecorder(path, pogodoctest.ModeReplay)
+		require.NoError(t, err)
+
+		changed := gdProps
+		changed.Data = map[string]interface{}{"name": "Other"}
+		client, err := pogodoc.PogodocClientInitWithConfig(baseURL, "secret-token", pogodoc.WithHTTPClient(recorder.Client()))
+		require.NoError(t, err)
+		_, err = client.StartGenerateDocument(changed, context.Background())
+		assert.ErrorIs(t, err, pogodoctest.ErrNoInteraction)
+	})
+
+	t.Run("missing cassette", func(t *testing.T) {
+		_, err := pogodoctest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), pogodoctest.ModeReplay)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestRecorderScrubsPresignedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := pogodoctest.NewRecorder(path, pogodoctest.ModeRecord)
+	require.NoError(t, err)
+	for _, query := range []string{
+		"X-Amz-Credential=AKIASIGV4&X-Amz-Signature=sigv4&X-Amz-Security-Token=session",
+		"AWSAccessKeyId=AKIASIGV2&Expires=1700000000&Signature=sigv2",
+	} {
+		resp, err := recorder.Client().Get(server.URL + "/upload?" + query)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	require.NoError(t, recorder.Save())
+
+	cassette, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"AKIASIGV4", "sigv4", "session", "AKIASIGV2", "sigv2"} {
+		assert.NotContains(t, string(cassette), secret)
+	}
+	assert.Contains(t, string(cassette), "AWSAccessKeyId=REDACTED")
+	assert.Contains(t, string(cassette), "Expires=1700000000")
+}
@@ -0,0 +1,278 @@
+// Package pogodoctest provides an in-process fake of the Pogodoc API for tests
+// that must run offline.
+//
+//	server := pogodoctest.NewServer()
+//	defer server.Close()
+//	client, err := pogodoc.PogodocClientInitWithConfig(server.URL, "test-token")
+//
+// The server implements every template and document endpoint, and serves the
+// presigned upload and download URLs it hands out itself. All state is kept in
+// memory; jobs can be delayed with WithJobLatency, their output customized with
+// WithRenderer, and any endpoint can be made to fail with Server.Fail.
+//
+// A Recorder records the traffic of a client, with the real API or the fake,
+// to a cassette file and replays it offline:
+//
+//	recorder, err := pogodoctest.NewRecorder("testdata/cassettes/render.json", pogodoctest.ModeReplay)
+//	client, err := pogodoc.PogodocClientInitWithConfig(baseURL, token, pogodoc.WithHTTPClient(recorder.Client()))
+package pogodoctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints of the fake server, named like the endpoints of the generated client
+// in logs and metrics. They identify the endpoint to fail in Server.Fail.
+const (
+	EndpointInitializeTemplateCreation = "templates.InitializeTemplateCreation"
+	EndpointSaveCreatedTemplate        = "templates.SaveCreatedTemplate"
+	EndpointUpdateTemplate             = "templates.UpdateTemplate"
+	EndpointDeleteTemplate             = "templates.DeleteTemplate"
+	EndpointExtractTemplateFiles       = "templates.ExtractTemplateFiles"
+	EndpointGenerateTemplatePreviews   = "templates.GenerateTemplatePreviews"
+	EndpointGeneratePresignedGetUrl    = "templates.GeneratePresignedGetUrl"
+	EndpointGetTemplateIndexHtml       = "templates.GetTemplateIndexHtml"
+	EndpointUploadTemplateIndexHtml    = "templates.UploadTemplateIndexHtml"
+	EndpointCloneTemplate              = "templates.CloneTemplate"
+	EndpointInitializeRenderJob        = "documents.InitializeRenderJob"
+	EndpointStartRenderJob             = "documents.StartRenderJob"
+	EndpointStartImmediateRender       = "documents.StartImmediateRender"
+	EndpointGetJobStatus               = "documents.GetJobStatus"
+	// EndpointUpload is a PUT to a presigned URL.
+	EndpointUpload = "upload"
+	// EndpointDownload is a GET of a presigned URL.
+	EndpointDownload = "download"
+)
+
+// Server is a fake Pogodoc API backed by an httptest.Server.
+// It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, to pass to PogodocClientInitWithConfig.
+	URL string
+
+	server     *httptest.Server
+	token      string
+	jobLatency time.Duration
+	renderer   Renderer
+
+	mu        sync.Mutex
+	nextId    int
+	templates map[string]*Template
+	jobs      map[string]*Job
+	objects   map[string]object
+	failures  map[string][]*Failure
+	requests  map[string]int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken makes the server reject requests to the API that are not
+// authenticated with the given token.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithJobLatency keeps render jobs in progress for the given duration after they
+// are started. Jobs finish as soon as they start by default.
+func WithJobLatency(latency time.Duration) Option {
+	return func(s *Server) {
+		s.jobLatency = latency
+	}
+}
+
+// WithRenderer replaces DefaultRenderer to produce the output of render jobs,
+// immediate renders and template previews.
+func WithRenderer(renderer Renderer) Option {
+	return func(s *Server) {
+		s.renderer = renderer
+	}
+}
+
+// NewServer starts a fake Pogodoc API. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		renderer:  DefaultRenderer,
+		templates: make(map[string]*Template),
+		jobs:      make(map[string]*Job),
+		objects:   make(map[string]object),
+		failures:  make(map[string][]*Failure),
+		requests:  make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.route(mux, "GET /templates/init", EndpointInitializeTemplateCreation, s.initializeTemplateCreation)
+	s.route(mux, "POST /templates/{templateId}", EndpointSaveCreatedTemplate, s.saveCreatedTemplate)
+	s.route(mux, "PUT /templates/{templateId}", EndpointUpdateTemplate, s.updateTemplate)
+	s.route(mux, "DELETE /templates/{templateId}", EndpointDeleteTemplate, s.deleteTemplate)
+	s.route(mux, "PATCH /templates/{templateId}/unzip", EndpointExtractTemplateFiles, s.extractTemplateFiles)
+	s.route(mux, "POST /templates/{templateId}/render-previews", EndpointGenerateTemplatePreviews, s.generateTemplatePreviews)
+	s.route(mux, "GET /templates/{templateId}/presigned-url", EndpointGeneratePresignedGetUrl, s.generatePresignedGetUrl)
+	s.route(mux, "GET /templates/{templateId}/index-html", EndpointGetTemplateIndexHtml, s.getTemplateIndexHtml)
+	s.route(mux, "POST /templates/{templateId}/index-html", EndpointUploadTemplateIndexHtml, s.uploadTemplateIndexHtml)
+	s.route(mux, "POST /templates/{templateId}/clone", EndpointCloneTemplate, s.cloneTemplate)
+	s.route(mux, "POST /documents/init", EndpointInitializeRenderJob, s.initializeRenderJob)
+	s.route(mux, "POST /documents/{jobId}/render", EndpointStartRenderJob, s.startRenderJob)
+	s.route(mux, "POST /documents/immediate-render", EndpointStartImmediateRender, s.startImmediateRender)
+	s.route(mux, "GET /jobs/{jobId}", EndpointGetJobStatus, s.getJobStatus)
+	s.route(mux, "PUT "+storagePath+"{key...}", EndpointUpload, s.putObject)
+	s.route(mux, "GET "+storagePath+"{key...}", EndpointDownload, s.getObject)
+
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an HTTP client for the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Failure is an error response injected with Server.Fail.
+type Failure struct {
+	// StatusCode defaults to 500 Internal Server Error.
+	StatusCode int
+	// Header is added to the response, e.g. to send Retry-After.
+	Header http.Header
+	// Body defaults to a JSON error envelope for API endpoints,
+	// and to an S3 error document for presigned URLs.
+	Body string
+	// Times is the number of requests to fail. Zero fails every request
+	// until ClearFailures is called.
+	Times int
+}
+
+// Fail makes the next requests to endpoint fail. Failures of the same endpoint
+// are applied in the order they were added.
+func (s *Server) Fail(endpoint string, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], &failure)
+}
+
+// ClearFailures removes all pending failures.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.failures)
+}
+
+// Requests returns the number of requests received by endpoint,
+// including the failed ones.
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+// route registers the handler of an endpoint, wrapped to count requests,
+// authenticate them and inject failures.
+func (s *Server) route(mux *http.ServeMux, pattern string, endpoint string, handler http.HandlerFunc) {
+	storage := endpoint == EndpointUpload || endpoint == EndpointDownload
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		failure := s.observe(endpoint)
+		if failure != nil {
+			writeFailure(w, *failure, storage)
+			return
+		}
+		if !storage && s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+			writeError(w, http.StatusUnauthorized, "Invalid API token")
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// observe counts a request to endpoint and returns the failure to inject, if any.
+func (s *Server) observe(endpoint string) *Failure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[endpoint]++
+
+	failures := s.failures[endpoint]
+	if len(failures) == 0 {
+		return nil
+	}
+	failure := *failures[0]
+	if failures[0].Times > 0 {
+		failures[0].Times--
+		if failures[0].Times == 0 {
+			s.failures[endpoint] = failures[1:]
+		}
+	}
+	return &failure
+}
+
+// newId returns a new unique ID with the given prefix.
+func (s *Server) newId(prefix string) string {
+	s.nextId++
+	return fmt.Sprintf("%s-%d", prefix, s.nextId)
+}
+
+// issue is a validation issue of an error envelope.
+type issue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// writeError writes the JSON error envelope of the Pogodoc API.
+func writeError(w http.ResponseWriter, statusCode int, message string, issues ...issue) {
+	code := strings.ReplaceAll(strings.ToLower(http.StatusText(statusCode)), " ", "_")
+	writeJSON(w, statusCode, map[string]interface{}{
+		"message": message,
+		"code":    code,
+		"issues":  issues,
+	})
+}
+
+// writeFailure writes an injected failure.
+func writeFailure(w http.ResponseWriter, failure Failure, storage bool) {
+	if failure.StatusCode == 0 {
+		failure.StatusCode = http.StatusInternalServerError
+	}
+	for name, values := range failure.Header {
+		w.Header()[name] = values
+	}
+	switch {
+	case failure.Body != "":
+		w.WriteHeader(failure.StatusCode)
+		_, _ = w.Write([]byte(failure.Body))
+	case storage:
+		writeStorageError(w, failure.StatusCode, "InjectedFailure", "Injected failure")
+	default:
+		writeError(w, failure.StatusCode, "Injected failure")
+	}
+}
+
+// writeJSON writes value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// decodeRequest decodes the JSON body of r into value, writing a validation
+// error and returning false if it is malformed.
+func decodeRequest(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body: "+err.Error())
+		return false
+	}
+	return true
+}
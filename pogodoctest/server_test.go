@@ -0,0 +1,249 @@
+package pogodoctest_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	pogodoc "github.com/Pogodoc/pogodoc-go"
+	"github.com/Pogodoc/pogodoc-go/pogodoctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastPolling = pogodoc.PollOptions{InitialDelay: -1, Interval: time.Millisecond}
+
+func newClient(t *testing.T, server *pogodoctest.Server) *pogodoc.PogodocClient {
+	client, err := pogodoc.PogodocClientInitWithConfig(server.URL, "test-token", pogodoc.WithRetryBaseDelay(time.Millisecond))
+	require.NoError(t, err)
+	return client
+}
+
+func templateArchive(t *testing.T, indexHtml string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"template/index.html":          indexHtml,
+		"template/styles/main.css":     "body {}",
+		"template/partials/index.html": "<p>partial</p>",
+	} {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestGenerateDocument(t *testing.T) {
+	server := pogodoctest.NewServer(pogodoctest.WithToken("test-token"))
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	templateId := server.AddTemplate(pogodoctest.Template{IndexHtml: "<h1>Hello</h1>"})
+
+	var output bytes.Buffer
+	jobStatus, err := client.GenerateDocumentToWriter(pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:       pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target:     pogodoc.InitializeRenderJobRequestTargetPdf,
+			TemplateId: pogodoc.String(templateId),
+		},
+		Data: map[string]interface{}{"name": "Pogodoc"},
+	}, &output, ctx, fastPolling)
+	require.NoError(t, err)
+	assert.Equal(t, "done", jobStatus.Status)
+	assert.Equal(t, templateId, *jobStatus.TemplateId)
+
+	var rendered map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &rendered))
+	assert.Equal(t, "<h1>Hello</h1>", rendered["template"])
+	assert.Equal(t, map[string]interface{}{"name": "Pogodoc"}, rendered["data"])
+
+	job, ok := server.Job(jobStatus.JobId)
+	require.True(t, ok)
+	assert.Equal(t, pogodoctest.JobStatusDone, job.Status)
+	assert.JSONEq(t, `{"name":"Pogodoc"}`, string(job.Data))
+	assert.Equal(t, output.Bytes(), job.Output)
+}
+
+func TestGenerateDocumentFromTemplateString(t *testing.T) {
+	server := pogodoctest.NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+
+	gdProps := pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:   pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target: pogodoc.InitializeRenderJobRequestTargetHtml,
+		},
+		Template: pogodoc.String("<p>inline</p>"),
+		Data:     map[string]interface{}{"id": 1},
+	}
+	jobStatus, err := client.GenerateDocument(gdProps, context.Background(), fastPolling)
+	require.NoError(t, err)
+	job, ok := server.Job(jobStatus.JobId)
+	require.True(t, ok)
+	assert.Equal(t, "<p>inline</p>", job.Template)
+
+	immediate, err := client.GenerateDocumentImmediate(gdProps, context.Background())
+	require.NoError(t, err)
+	var output bytes.Buffer
+	require.NoError(t, client.DownloadResult(immediate.Url, &output, context.Background()))
+	assert.Contains(t, output.String(), "<p>inline</p>")
+}
+
+func TestTemplateWorkflow(t *testing.T) {
+	server := pogodoctest.NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	templateId, err := client.SaveTemplateFromFileStream(
+		pogodoc.FileStreamFromBytes(templateArchive(t, "<h1>v1</h1>")),
+		pogodoc.SaveCreatedTemplateRequestTemplateInfo{
+			Title:      "Invoice",
+			Type:       pogodoc.SaveCreatedTemplateRequestTemplateInfoTypeHtml,
+			Categories: []pogodoc.SaveCreatedTemplateRequestTemplateInfoCategoriesItem{pogodoc.SaveCreatedTemplateRequestTemplateInfoCategoriesItemInvoice},
+		},
+		ctx,
+	)
+	require.NoError(t, err)
+
+	template, ok := server.Template(templateId)
+	require.True(t, ok)
+	assert.True(t, template.Saved)
+	assert.Equal(t, "Invoice", template.Title)
+	assert.Equal(t, []string{"invoice"}, template.Categories)
+	assert.Equal(t, "<h1>v1</h1>", template.IndexHtml)
+	assert.Equal(t, []byte("body {}"), template.Files["template/styles/main.css"])
+
+	_, err = client.UpdateTemplateFromFileStream(
+		templateId,
+		pogodoc.FileStreamFromBytes(templateArchive(t, "<h1>v2</h1>")),
+		pogodoc.UpdateTemplateRequestTemplateInfo{Title: "Invoice v2", Type: pogodoc.UpdateTemplateRequestTemplateInfoTypeHtml},
+		ctx,
+	)
+	require.NoError(t, err)
+	indexHtml, err := client.Templates.GetTemplateIndexHtml(ctx, templateId)
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>v2</h1>", indexHtml.IndexHtml)
+
+	clone, err := client.Templates.CloneTemplate(ctx, templateId)
+	require.NoError(t, err)
+	require.NoError(t, client.Templates.UploadTemplateIndexHtml(ctx, clone.NewTemplateId, &pogodoc.UploadTemplateIndexHtmlRequest{IndexHtml: "<h1>clone</h1>"}))
+	template, ok = server.Template(clone.NewTemplateId)
+	require.True(t, ok)
+	assert.Equal(t, "Invoice v2", template.Title)
+	assert.Equal(t, "<h1>clone</h1>", template.IndexHtml)
+
+	presigned, err := client.Templates.GeneratePresignedGetUrl(ctx, templateId)
+	require.NoError(t, err)
+	var archive bytes.Buffer
+	require.NoError(t, client.DownloadResult(presigned.PresignedUrl, &archive, ctx))
+	assert.Equal(t, templateArchive(t, "<h1>v2</h1>")[:4], archive.Bytes()[:4])
+
+	deleted, err := client.Templates.DeleteTemplate(ctx, templateId)
+	require.NoError(t, err)
+	assert.Equal(t, templateId, deleted.TemplateId)
+	_, err = client.Templates.GetTemplateIndexHtml(ctx, templateId)
+	assert.ErrorIs(t, err, pogodoc.ErrNotFound)
+}
+
+func TestJobLatency(t *testing.T) {
+	server := pogodoctest.NewServer(pogodoctest.WithJobLatency(50 * time.Millisecond))
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	templateId := server.AddTemplate(pogodoctest.Template{IndexHtml: "<h1>Hello</h1>"})
+	job, err := client.StartGenerateDocumentJob(pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:       pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target:     pogodoc.InitializeRenderJobRequestTargetPdf,
+			TemplateId: pogodoc.String(templateId),
+		},
+	}, ctx)
+	require.NoError(t, err)
+
+	jobStatus, err := job.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, pogodoc.JobStatusInProgress, pogodoc.StatusOf(jobStatus))
+
+	jobStatus, err = job.Wait(ctx, fastPolling)
+	require.NoError(t, err)
+	assert.Equal(t, pogodoc.JobStatusDone, pogodoc.StatusOf(jobStatus))
+	assert.GreaterOrEqual(t, jobStatus.Output.Metadata.RenderTime, float64(50))
+}
+
+func TestFailures(t *testing.T) {
+	gdProps := pogodoc.GenerateDocumentProps{
+		InitializeRenderJobRequest: pogodoc.InitializeRenderJobRequest{
+			Type:   pogodoc.InitializeRenderJobRequestTypeHtml,
+			Target: pogodoc.InitializeRenderJobRequestTargetPdf,
+		},
+		Template: pogodoc.String("<h1>Hello</h1>"),
+	}
+
+	t.Run("retried", func(t *testing.T) {
+		server := pogodoctest.NewServer()
+		defer server.Close()
+		server.Fail(pogodoctest.EndpointInitializeRenderJob, pogodoctest.Failure{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+		_, err := newClient(t, server).GenerateDocument(gdProps, context.Background(), fastPolling)
+		require.NoError(t, err)
+		assert.Equal(t, 2, server.Requests(pogodoctest.EndpointInitializeRenderJob))
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		server := pogodoctest.NewServer()
+		defer server.Close()
+		server.Fail(pogodoctest.EndpointUpload, pogodoctest.Failure{StatusCode: http.StatusForbidden})
+
+		_, err := newClient(t, server).StartGenerateDocument(gdProps, context.Background())
+		assert.ErrorIs(t, err, pogodoc.ErrUploadFailed)
+		assert.ErrorContains(t, err, "InjectedFailure")
+		assert.Zero(t, server.Requests(pogodoctest.EndpointStartRenderJob))
+	})
+
+	t.Run("render", func(t *testing.T) {
+		server := pogodoctest.NewServer(pogodoctest.WithRenderer(func(pogodoctest.RenderRequest) ([]byte, error) {
+			return nil, errors.New("template syntax error")
+		}))
+		defer server.Close()
+
+		_, err := newClient(t, server).GenerateDocument(gdProps, context.Background(), fastPolling)
+		var renderErr *pogodoc.RenderJobError
+		require.ErrorAs(t, err, &renderErr)
+		assert.Equal(t, "template syntax error", renderErr.Message)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		server := pogodoctest.NewServer()
+		defer server.Close()
+
+		invalid := gdProps
+		invalid.InitializeRenderJobRequest.Target = "gif"
+		_, err := newClient(t, server).StartGenerateDocument(invalid, context.Background())
+		assert.ErrorIs(t, err, pogodoc.ErrValidation)
+		var apiErr *pogodoc.APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Issues, 1)
+		assert.Equal(t, "target", apiErr.Issues[0].Path)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		server := pogodoctest.NewServer(pogodoctest.WithToken("other-token"))
+		defer server.Close()
+
+		_, err := newClient(t, server).StartGenerateDocument(gdProps, context.Background())
+		assert.ErrorIs(t, err, pogodoc.ErrUnauthorized)
+	})
+}
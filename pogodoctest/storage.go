@@ -0,0 +1,101 @@
+package pogodoctest
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// storagePath is the path under which the server serves presigned URLs.
+const storagePath = "/storage/"
+
+// presignedSignature is the signature of every presigned URL of the server.
+const presignedSignature = "pogodoctest"
+
+// object is a file stored behind a presigned URL.
+type object struct {
+	data        []byte
+	contentType string
+}
+
+// presignedURL returns a presigned URL to upload or download the object stored at key.
+func (s *Server) presignedURL(key string) string {
+	query := url.Values{
+		"X-Amz-Algorithm": []string{"AWS4-HMAC-SHA256"},
+		"X-Amz-Signature": []string{presignedSignature},
+	}
+	return s.URL + storagePath + key + "?" + query.Encode()
+}
+
+// Object returns the data stored at key, e.g. "jobs/job-1/data" for the
+// data uploaded for a render job.
+func (s *Server) Object(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[key]
+	return obj.data, ok
+}
+
+// object returns the data stored at key. The caller must hold s.mu.
+func (s *Server) object(key string) ([]byte, bool) {
+	obj, ok := s.objects[key]
+	return obj.data, ok
+}
+
+// putObject stores an object uploaded to a presigned URL.
+func (s *Server) putObject(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("X-Amz-Signature") != presignedSignature {
+		writeStorageError(w, http.StatusForbidden, "AccessDenied", "Request has invalid signature")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeStorageError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.objects[r.PathValue("key")] = object{data: data, contentType: r.Header.Get("Content-Type")}
+	s.mu.Unlock()
+
+	sum := md5.Sum(data)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// getObject serves an object downloaded from a presigned URL.
+func (s *Server) getObject(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("X-Amz-Signature") != presignedSignature {
+		writeStorageError(w, http.StatusForbidden, "AccessDenied", "Request has invalid signature")
+		return
+	}
+
+	s.mu.Lock()
+	obj, ok := s.objects[r.PathValue("key")]
+	s.mu.Unlock()
+	if !ok {
+		writeStorageError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	if obj.contentType != "" {
+		w.Header().Set("Content-Type", obj.contentType)
+	}
+	http.ServeContent(w, r, r.PathValue("key"), time.Time{}, bytes.NewReader(obj.data))
+}
+
+// writeStorageError writes an S3 error document.
+func writeStorageError(w http.ResponseWriter, statusCode int, code string, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: code, Message: message})
+}
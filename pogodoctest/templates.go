@@ -0,0 +1,340 @@
+package pogodoctest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"path"
+	"strings"
+
+	pogodocgoclient "github.com/Pogodoc/pogodoc-go/client"
+)
+
+// Template is a template stored by the server.
+type Template struct {
+	Id          string
+	Title       string
+	Description string
+	Type        string
+	Categories  []string
+	SampleData  map[string]interface{}
+	// IndexHtml is the index.html of the template, rendered by render jobs
+	// that reference the template.
+	IndexHtml string
+	// Files are the files extracted from the uploaded template archive, by path.
+	Files map[string][]byte
+	// Saved reports whether the template was saved with SaveCreatedTemplate,
+	// as opposed to being content initialized for a template update.
+	Saved bool
+}
+
+// clone returns a deep copy of the template.
+func (t *Template) clone() *Template {
+	clone := *t
+	clone.Categories = append([]string(nil), t.Categories...)
+	clone.SampleData = maps.Clone(t.SampleData)
+	clone.Files = maps.Clone(t.Files)
+	return &clone
+}
+
+// AddTemplate stores a saved template, to render documents from without going
+// through the template creation workflow. A template ID is generated if
+// template.Id is empty. It returns the ID of the template.
+func (s *Server) AddTemplate(template Template) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := template.clone()
+	if stored.Id == "" {
+		stored.Id = s.newId("template")
+	}
+	stored.Saved = true
+	s.templates[stored.Id] = stored
+	return stored.Id
+}
+
+// Template returns a copy of the template with the given ID.
+func (s *Server) Template(templateId string) (Template, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.templates[templateId]
+	if !ok {
+		return Template{}, false
+	}
+	return *template.clone(), true
+}
+
+// archiveKey is the key of the archive uploaded for a template.
+func archiveKey(templateId string) string {
+	return "templates/" + templateId + "/template.zip"
+}
+
+// lookupTemplate returns the template of the request path, writing a 404 if it
+// does not exist. The caller must hold s.mu.
+func (s *Server) lookupTemplate(w http.ResponseWriter, r *http.Request) (*Template, bool) {
+	template, ok := s.templates[r.PathValue("templateId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template not found")
+	}
+	return template, ok
+}
+
+func (s *Server) initializeTemplateCreation(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	templateId := s.newId("template")
+	s.templates[templateId] = &Template{Id: templateId}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"templateId":                 templateId,
+		"presignedTemplateUploadUrl": s.presignedURL(archiveKey(templateId)),
+	})
+}
+
+func (s *Server) extractTemplateFiles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	archive, ok := s.object(archiveKey(template.Id))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Template archive has not been uploaded")
+		return
+	}
+
+	files, err := extractArchive(archive)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid template archive: "+err.Error())
+		return
+	}
+	template.Files = files
+	template.IndexHtml = indexHtml(files)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) generateTemplatePreviews(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.GenerateTemplatePreviewsRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if _, err := pogodocgoclient.NewGenerateTemplatePreviewsRequestTypeFromString(string(request.Type)); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request", issue{Path: "type", Message: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	template, ok := s.lookupTemplate(w, r)
+	var renderRequest RenderRequest
+	if ok {
+		renderRequest = RenderRequest{
+			TemplateId: template.Id,
+			Type:       string(request.Type),
+			Template:   template.IndexHtml,
+			Data:       request.Data,
+		}
+	}
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	renderRequest.RawData, _ = json.Marshal(request.Data)
+
+	response := make(map[string]interface{})
+	for _, target := range []string{"png", "pdf"} {
+		renderRequest.Target = target
+		job, err := s.renderNow(renderRequest)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Rendering preview failed: "+err.Error())
+			return
+		}
+		response[target+"Preview"] = map[string]string{
+			"url":   s.presignedURL(outputKey(job)),
+			"jobId": job.Id,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) saveCreatedTemplate(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.SaveCreatedTemplateRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if request.TemplateInfo == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request", issue{Path: "templateInfo", Message: "Required"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	info := request.TemplateInfo
+	template.Title = info.Title
+	template.Description = info.Description
+	template.Type = string(info.Type)
+	template.SampleData = info.SampleData
+	template.Categories = nil
+	for _, category := range info.Categories {
+		template.Categories = append(template.Categories, string(category))
+	}
+	template.Saved = true
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) updateTemplate(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.UpdateTemplateRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	content, ok := s.templates[request.ContentId]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid request", issue{Path: "contentId", Message: "Template content not found"})
+		return
+	}
+
+	template.IndexHtml = content.IndexHtml
+	template.Files = maps.Clone(content.Files)
+	if archive, ok := s.objects[archiveKey(content.Id)]; ok {
+		s.objects[archiveKey(template.Id)] = archive
+	}
+	if info := request.TemplateInfo; info != nil {
+		template.Title = info.Title
+		template.Description = info.Description
+		template.Type = string(info.Type)
+		template.SampleData = info.SampleData
+		template.Categories = nil
+		for _, category := range info.Categories {
+			template.Categories = append(template.Categories, string(category))
+		}
+	}
+	if content.Id != template.Id && !content.Saved {
+		delete(s.templates, content.Id)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"newContentId": request.ContentId})
+}
+
+func (s *Server) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	delete(s.templates, template.Id)
+	delete(s.objects, archiveKey(template.Id))
+	writeJSON(w, http.StatusOK, map[string]string{"templateId": template.Id})
+}
+
+func (s *Server) generatePresignedGetUrl(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.objects[archiveKey(template.Id)]; !ok {
+		writeError(w, http.StatusNotFound, "Template archive not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"presignedUrl": s.presignedURL(archiveKey(template.Id))})
+}
+
+func (s *Server) getTemplateIndexHtml(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"indexHtml": template.IndexHtml})
+}
+
+func (s *Server) uploadTemplateIndexHtml(w http.ResponseWriter, r *http.Request) {
+	var request pogodocgoclient.UploadTemplateIndexHtmlRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	template.IndexHtml = request.IndexHtml
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) cloneTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	template, ok := s.lookupTemplate(w, r)
+	if !ok {
+		return
+	}
+	clone := template.clone()
+	clone.Id = s.newId("template")
+	s.templates[clone.Id] = clone
+	if archive, ok := s.objects[archiveKey(template.Id)]; ok {
+		s.objects[archiveKey(clone.Id)] = archive
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"newTemplateId": clone.Id})
+}
+
+// extractArchive returns the files of a ZIP archive by path.
+func extractArchive(archive []byte) (map[string][]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[file.Name] = data
+	}
+	return files, nil
+}
+
+// indexHtml returns the least nested index.html of the files, if any.
+func indexHtml(files map[string][]byte) string {
+	var best string
+	for name := range files {
+		if path.Base(name) != "index.html" {
+			continue
+		}
+		if best == "" || strings.Count(name, "/") < strings.Count(best, "/") ||
+			(strings.Count(name, "/") == strings.Count(best, "/") && name < best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return string(files[best])
+}
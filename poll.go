@@ -0,0 +1,242 @@
+package pogodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultPollInitialDelay = 1 * time.Second
+	defaultPollInterval     = 500 * time.Millisecond
+	defaultPollMaxAttempts  = 60
+)
+
+// ErrJobTimeout is returned (wrapped in a *JobTimeoutError) when a render job
+// does not reach a terminal state within the configured polling limits.
+var ErrJobTimeout = errors.New("pogodoc: timed out waiting for render job")
+
+// JobTimeoutError is returned by PollForJobCompletion when polling gives up
+// before the job completes. It carries the last status observed from the API.
+type JobTimeoutError struct {
+	JobId      string
+	Attempts   int
+	LastStatus *GetJobStatusResponse
+}
+
+func (e *JobTimeoutError) Error() string {
+	if e.LastStatus != nil {
+		return fmt.Sprintf("job %s did not complete after %d status checks (last status %q)", e.JobId, e.Attempts, e.LastStatus.Status)
+	}
+	return fmt.Sprintf("job %s did not complete after %d status checks", e.JobId, e.Attempts)
+}
+
+// Is makes a *JobTimeoutError match ErrJobTimeout with errors.Is.
+func (e *JobTimeoutError) Is(target error) bool {
+	return target == ErrJobTimeout
+}
+
+// BackoffStrategy returns the delay to wait after the given zero-based poll
+// attempt, given the configured base interval.
+type BackoffStrategy func(attempt int, interval time.Duration) time.Duration
+
+// FixedBackoff waits the base interval between every poll.
+func FixedBackoff() BackoffStrategy {
+	return func(_ int, interval time.Duration) time.Duration {
+		return interval
+	}
+}
+
+// ExponentialBackoff multiplies the base interval by multiplier after every poll,
+// never waiting longer than maxInterval. A maxInterval of zero means no cap,
+// other than the largest time.Duration.
+func ExponentialBackoff(multiplier float64, maxInterval time.Duration) BackoffStrategy {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	if maxInterval <= 0 {
+		maxInterval = math.MaxInt64
+	}
+	return func(attempt int, interval time.Duration) time.Duration {
+		// The product overflows to +Inf for large attempts, so it is clamped
+		// before being converted back to a Duration.
+		delay := float64(interval) * math.Pow(multiplier, float64(attempt))
+		if delay >= float64(maxInterval) {
+			return maxInterval
+		}
+		return time.Duration(delay)
+	}
+}
+
+// JitteredBackoff randomizes the delay produced by strategy by up to the given
+// fraction, so that a fraction of 0.25 yields a delay in the range of 75%-100%.
+func JitteredBackoff(strategy BackoffStrategy, fraction float64) BackoffStrategy {
+	if strategy == nil {
+		strategy = FixedBackoff()
+	}
+	fraction = min(max(fraction, 0), 1)
+	return func(attempt int, interval time.Duration) time.Duration {
+		delay := strategy(attempt, interval)
+		jitter := time.Duration(rand.Float64() * fraction * float64(delay))
+		return delay - jitter
+	}
+}
+
+// PollOptions configures how PollForJobCompletion waits for a render job.
+// Zero values fall back to the defaults: a 1s initial delay followed by
+// up to 60 status checks 500ms apart.
+type PollOptions struct {
+	// InitialDelay is how long to wait before the first status check.
+	// A negative value disables the initial delay.
+	InitialDelay time.Duration
+	// Interval is the base delay between status checks.
+	Interval time.Duration
+	// MaxWait bounds the total time spent polling, including the initial delay.
+	// Zero means polling is only bounded by MaxAttempts.
+	MaxWait time.Duration
+	// MaxAttempts bounds the number of status checks.
+	MaxAttempts int
+	// Backoff computes the delay between status checks. Defaults to FixedBackoff.
+	Backoff BackoffStrategy
+}
+
+// resolvePollOptions returns the optional PollOptions of a method with defaults applied.
+// More than one value is an error, rather than silently ignoring the others.
+func resolvePollOptions(opts []PollOptions) (PollOptions, error) {
+	var options PollOptions
+	switch len(opts) {
+	case 0:
+	case 1:
+		options = opts[0]
+	default:
+		return options, fmt.Errorf("pogodoc: at most one PollOptions value can be given, got %d", len(opts))
+	}
+	if options.InitialDelay == 0 {
+		options.InitialDelay = defaultPollInitialDelay
+	} else if options.InitialDelay < 0 {
+		options.InitialDelay = 0
+	}
+	if options.Interval <= 0 {
+		options.Interval = defaultPollInterval
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultPollMaxAttempts
+	}
+	if options.Backoff == nil {
+		options.Backoff = FixedBackoff()
+	}
+	return options, nil
+}
+
+// sleepContext waits for the given duration or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pollDelay clamps delay to the time remaining before deadline. It reports false
+// once the deadline has passed.
+func pollDelay(delay time.Duration, deadline time.Time) (time.Duration, bool) {
+	if deadline.IsZero() {
+		return delay, true
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 0, false
+	}
+	return min(delay, remaining), true
+}
+
+// pollJob implements PollForJobCompletion. If observe is non-nil, it is called
+// with every status response received from the API.
+func (c *PogodocClient) pollJob(
+	ctx context.Context,
+	jobId string,
+	pollOpts PollOptions,
+	observe func(*GetJobStatusResponse),
+) (_ *GetJobStatusResponse, err error) {
+	ctx, span := c.startSpan(ctx, "PollForJobCompletion", attrJobId.String(jobId))
+	defer func() { endSpan(span, err) }()
+
+	logger := c.logger()
+	metrics := c.metrics()
+
+	var deadline time.Time
+	if pollOpts.MaxWait > 0 {
+		deadline = time.Now().Add(pollOpts.MaxWait)
+	}
+
+	var (
+		lastStatus *GetJobStatusResponse
+		attempts   int
+	)
+	delay := pollOpts.InitialDelay
+	for attempts < pollOpts.MaxAttempts {
+		wait, ok := pollDelay(delay, deadline)
+		if !ok {
+			break
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, fmt.Errorf("polling job %s: %w", jobId, err)
+		}
+
+		jobStatus, err := c.getJobStatusAttempt(ctx, jobId, attempts+1)
+		if err != nil {
+			return nil, &StepError{Step: StepGetJobStatus, Err: err}
+		}
+		lastStatus = jobStatus
+		span.SetAttributes(jobStatusAttributes(jobStatus)...)
+		if observe != nil {
+			observe(jobStatus)
+		}
+
+		status := StatusOf(jobStatus)
+		logger.DebugContext(ctx, "polled job status", slog.String("job_id", jobId), slog.Int("attempt", attempts+1), slog.String("status", string(status)))
+		switch status {
+		case JobStatusDone:
+			logger.InfoContext(ctx, "render job finished", slog.String("job_id", jobId), slog.Int("attempts", attempts+1))
+			metrics.ObservePollAttempts(attempts+1, string(status))
+			if jobStatus.Output != nil && jobStatus.Output.Metadata != nil {
+				metrics.ObserveRenderTime(jobStatus.Target, jobStatus.Output.Metadata.RenderTime)
+			}
+			return jobStatus, nil
+		case JobStatusFailed:
+			err := newRenderJobError(jobStatus)
+			logger.WarnContext(ctx, "render job failed", slog.String("job_id", jobId), slog.Any("error", err))
+			metrics.ObservePollAttempts(attempts+1, string(status))
+			return jobStatus, err
+		}
+		delay = pollOpts.Backoff(attempts, pollOpts.Interval)
+		attempts++
+	}
+
+	logger.WarnContext(ctx, "render job did not finish in time", slog.String("job_id", jobId), slog.Int("attempts", attempts))
+	metrics.ObservePollAttempts(attempts, string(StatusOf(lastStatus)))
+	return nil, &JobTimeoutError{
+		JobId:      jobId,
+		Attempts:   attempts,
+		LastStatus: lastStatus,
+	}
+}
+
+// getJobStatusAttempt fetches the job status once, in a span for the poll attempt.
+func (c *PogodocClient) getJobStatusAttempt(ctx context.Context, jobId string, attempt int) (*GetJobStatusResponse, error) {
+	ctx, span := c.startSpan(ctx, "PollAttempt", attrJobId.String(jobId), attrPollAttempt.Int(attempt))
+	jobStatus, err := c.documents().GetJobStatus(ctx, jobId)
+	span.SetAttributes(jobStatusAttributes(jobStatus)...)
+	endSpan(span, err)
+	return jobStatus, err
+}
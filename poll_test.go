@@ -0,0 +1,255 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Pogodoc/pogodoc-go/client/client"
+	"github.com/Pogodoc/pogodoc-go/client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newJobStatusServer returns a server that answers GET /jobs/{id} with the given
+// statuses in order, repeating the last one once they are exhausted.
+func newJobStatusServer(t *testing.T, statuses ...GetJobStatusResponse) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(statuses[n]))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newTestClient(t *testing.T, baseURL string) *PogodocClient {
+	t.Helper()
+	client, err := PogodocClientInitWithConfig(baseURL, "test-token")
+	require.NoError(t, err)
+	return client
+}
+
+func TestPollForJobCompletion(t *testing.T) {
+	fastPoll := PollOptions{
+		InitialDelay: -1,
+		Interval:     time.Millisecond,
+	}
+
+	t.Run("works with a client built around a generated client", func(t *testing.T) {
+		server, _ := newJobStatusServer(t, GetJobStatusResponse{JobId: "job", Status: "done"})
+		client := &PogodocClient{Client: client.NewClient(option.WithBaseURL(server.URL), option.WithToken("test-token"))}
+
+		job, err := client.PollForJobCompletion("job", context.Background(), fastPoll)
+		require.NoError(t, err)
+		assert.Equal(t, "done", job.Status)
+	})
+
+	t.Run("returns the job once it is done", func(t *testing.T) {
+		server, calls := newJobStatusServer(t,
+			GetJobStatusResponse{JobId: "job", Status: "pending"},
+			GetJobStatusResponse{JobId: "job", Status: "done"},
+		)
+		client := newTestClient(t, server.URL)
+
+		job, err := client.PollForJobCompletion("job", context.Background(), fastPoll)
+		require.NoError(t, err)
+		assert.Equal(t, "done", job.Status)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("stops immediately when the job fails", func(t *testing.T) {
+		server, calls := newJobStatusServer(t,
+			GetJobStatusResponse{JobId: "job", Status: "in-progress"},
+			GetJobStatusResponse{
+				JobId:      "job",
+				TemplateId: String("template"),
+				Target:     "pdf",
+				Status:     "done",
+				Success:    Bool(false),
+				Error:      String("template crashed"),
+			},
+			GetJobStatusResponse{JobId: "job", Status: "done"},
+		)
+		client := newTestClient(t, server.URL)
+
+		job, err := client.PollForJobCompletion("job", context.Background(), fastPoll)
+		var renderErr *RenderJobError
+		require.True(t, errors.As(err, &renderErr))
+		assert.Equal(t, "job", renderErr.JobId)
+		assert.Equal(t, "template", renderErr.TemplateId)
+		assert.Equal(t, "pdf", renderErr.Target)
+		assert.Equal(t, "template crashed", renderErr.Message)
+		require.NotNil(t, job)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("returns ErrJobTimeout with the last status after MaxAttempts", func(t *testing.T) {
+		server, calls := newJobStatusServer(t, GetJobStatusResponse{JobId: "job", Status: "pending"})
+		client := newTestClient(t, server.URL)
+
+		opts := fastPoll
+		opts.MaxAttempts = 3
+		_, err := client.PollForJobCompletion("job", context.Background(), opts)
+		require.ErrorIs(t, err, ErrJobTimeout)
+
+		var timeoutErr *JobTimeoutError
+		require.True(t, errors.As(err, &timeoutErr))
+		assert.Equal(t, 3, timeoutErr.Attempts)
+		require.NotNil(t, timeoutErr.LastStatus)
+		assert.Equal(t, "pending", timeoutErr.LastStatus.Status)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("stops polling once MaxWait elapses", func(t *testing.T) {
+		server, _ := newJobStatusServer(t, GetJobStatusResponse{JobId: "job", Status: "pending"})
+		client := newTestClient(t, server.URL)
+
+		start := time.Now()
+		_, err := client.PollForJobCompletion("job", context.Background(), PollOptions{
+			InitialDelay: -1,
+			Interval:     10 * time.Millisecond,
+			MaxWait:      50 * time.Millisecond,
+			MaxAttempts:  1000,
+		})
+		require.ErrorIs(t, err, ErrJobTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("honors context cancellation between polls", func(t *testing.T) {
+		server, _ := newJobStatusServer(t, GetJobStatusResponse{JobId: "job", Status: "pending"})
+		client := newTestClient(t, server.URL)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.PollForJobCompletion("job", ctx, PollOptions{
+			InitialDelay: -1,
+			Interval:     time.Hour,
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("rejects more than one PollOptions value", func(t *testing.T) {
+		server, calls := newJobStatusServer(t, GetJobStatusResponse{JobId: "job", Status: "done"})
+		client := newTestClient(t, server.URL)
+
+		_, err := client.PollForJobCompletion("job", context.Background(), fastPoll, PollOptions{MaxAttempts: 1})
+		assert.EqualError(t, err, "pogodoc: at most one PollOptions value can be given, got 2")
+		assert.Zero(t, calls.Load())
+	})
+}
+
+func TestStatusOf(t *testing.T) {
+	assert.Equal(t, JobStatusPending, StatusOf(nil))
+	assert.Equal(t, JobStatusInProgress, StatusOf(&GetJobStatusResponse{Status: "in-progress"}))
+	assert.Equal(t, JobStatusDone, StatusOf(&GetJobStatusResponse{Status: "done", Success: Bool(true)}))
+	assert.Equal(t, JobStatusFailed, StatusOf(&GetJobStatusResponse{Status: "done", Success: Bool(false)}))
+	assert.Equal(t, JobStatusFailed, StatusOf(&GetJobStatusResponse{Status: "in-progress", Error: String("boom")}))
+
+	assert.False(t, JobStatusPending.IsTerminal())
+	assert.False(t, JobStatusInProgress.IsTerminal())
+	assert.True(t, JobStatusDone.IsTerminal())
+	assert.True(t, JobStatusFailed.IsTerminal())
+}
+
+func TestBackoffStrategies(t *testing.T) {
+	interval := 100 * time.Millisecond
+
+	fixed := FixedBackoff()
+	assert.Equal(t, interval, fixed(0, interval))
+	assert.Equal(t, interval, fixed(5, interval))
+
+	exponential := ExponentialBackoff(2, time.Second)
+	assert.Equal(t, 100*time.Millisecond, exponential(0, interval))
+	assert.Equal(t, 200*time.Millisecond, exponential(1, interval))
+	assert.Equal(t, 400*time.Millisecond, exponential(2, interval))
+	assert.Equal(t, time.Second, exponential(10, interval))
+	assert.Equal(t, time.Second, exponential(5000, interval))
+	assert.Equal(t, time.Duration(math.MaxInt64), ExponentialBackoff(2, 0)(5000, interval))
+
+	jittered := JitteredBackoff(fixed, 0.25)
+	for range 100 {
+		delay := jittered(0, interval)
+		assert.GreaterOrEqual(t, delay, 75*time.Millisecond)
+		assert.LessOrEqual(t, delay, interval)
+	}
+}
+
+// recordingMetrics records the measurements reported by the client.
+type recordingMetrics struct {
+	NoopMetrics
+	mu           sync.Mutex
+	requests     []string
+	uploadBytes  int64
+	pollAttempts []int
+	renderTimes  []float64
+	retries      []int
+}
+
+func (m *recordingMetrics) ObserveRequest(endpoint string, statusCode int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%s %d", endpoint, statusCode))
+}
+
+func (m *recordingMetrics) IncRetry(statusCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, statusCode)
+}
+
+func (m *recordingMetrics) AddUploadBytes(bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.uploadBytes += bytes
+}
+
+func (m *recordingMetrics) ObservePollAttempts(attempts int, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollAttempts = append(m.pollAttempts, attempts)
+}
+
+func (m *recordingMetrics) ObserveRenderTime(_ string, renderTime float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renderTimes = append(m.renderTimes, renderTime)
+}
+
+func TestMetrics(t *testing.T) {
+	server, _ := newJobStatusServer(t,
+		GetJobStatusResponse{JobId: "job", Status: "in-progress"},
+		GetJobStatusResponse{JobId: "job", Target: "pdf", Status: "done", Output: &GetJobStatusResponseOutput{
+			Metadata: &GetJobStatusResponseOutputMetadata{RenderTime: 1200},
+		}},
+	)
+
+	metrics := &recordingMetrics{}
+	client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithMetrics(metrics))
+	require.NoError(t, err)
+
+	_, err = client.PollForJobCompletion("job", context.Background(), PollOptions{InitialDelay: -1, Interval: time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, client.UploadToPresignedURL(context.Background(), server.URL, FileStreamFromBytes([]byte("test data")), "application/json"))
+
+	assert.Equal(t, []string{"documents.GetJobStatus 200", "documents.GetJobStatus 200", "upload 200"}, metrics.requests)
+	assert.Equal(t, []int{2}, metrics.pollAttempts)
+	assert.Equal(t, []float64{1200}, metrics.renderTimes)
+	assert.Equal(t, int64(9), metrics.uploadBytes)
+}
@@ -0,0 +1,42 @@
+package pogodoc
+
+import "io"
+
+// ProgressFunc is called as an upload or download makes progress, with the number of
+// bytes transferred so far and the total number of bytes, or -1 if the total is unknown.
+// It is called from the goroutine performing the transfer, so it should return quickly.
+type ProgressFunc func(bytesSent, total int64)
+
+// progressReader reports the bytes read from the wrapped body.
+type progressReader struct {
+	io.ReadCloser
+	sent     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.ReadCloser.Read(b)
+	if n > 0 {
+		p.sent += int64(n)
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+// progressWriter reports the bytes written to the wrapped writer.
+type progressWriter struct {
+	io.Writer
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.Writer.Write(b)
+	if n > 0 {
+		p.written += int64(n)
+		p.progress(p.written, p.total)
+	}
+	return n, err
+}
@@ -0,0 +1,107 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressRecorder collects the values reported to a ProgressFunc.
+type progressRecorder struct {
+	sent  []int64
+	total []int64
+}
+
+func (p *progressRecorder) record(bytesSent, total int64) {
+	p.sent = append(p.sent, bytesSent)
+	p.total = append(p.total, total)
+}
+
+func (p *progressRecorder) last() (int64, int64) {
+	return p.sent[len(p.sent)-1], p.total[len(p.total)-1]
+}
+
+func newDiscardServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUploadProgress(t *testing.T) {
+	t.Run("file streams report bytes sent", func(t *testing.T) {
+		server := newDiscardServer(t)
+		progress := &progressRecorder{}
+
+		fsProps := FileStreamFromBytes(bytes.Repeat([]byte("x"), 100_000)).WithProgress(progress.record)
+		require.NoError(t, newTestClient(t, server.URL).UploadToPresignedURL(context.Background(), server.URL, fsProps, "application/zip"))
+
+		sent, total := progress.last()
+		assert.Equal(t, int64(100_000), sent)
+		assert.Equal(t, int64(100_000), total)
+		assert.IsIncreasing(t, progress.sent)
+	})
+
+	t.Run("template methods accept WithUploadProgress", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			switch r.URL.Path {
+			case "/templates/init":
+				assert.NoError(t, json.NewEncoder(w).Encode(InitializeTemplateCreationResponse{
+					TemplateId:                 "template",
+					PresignedTemplateUploadUrl: server.URL + "/upload",
+				}))
+			case "/templates/template/render-previews":
+				assert.NoError(t, json.NewEncoder(w).Encode(GenerateTemplatePreviewsResponse{
+					PngPreview: &GenerateTemplatePreviewsResponsePngPreview{JobId: "png"},
+					PdfPreview: &GenerateTemplatePreviewsResponsePdfPreview{JobId: "pdf"},
+				}))
+			}
+		}))
+		defer server.Close()
+
+		path := filepath.Join(t.TempDir(), "template.zip")
+		require.NoError(t, os.WriteFile(path, []byte("zip bytes"), 0o644))
+
+		progress := &progressRecorder{}
+		_, err := newTestClient(t, server.URL).SaveTemplate(path, SaveCreatedTemplateRequestTemplateInfo{
+			Title: "Invoice",
+			Type:  SaveCreatedTemplateRequestTemplateInfoTypeHtml,
+		}, context.Background(), WithUploadProgress(progress.record))
+		require.NoError(t, err)
+
+		sent, total := progress.last()
+		assert.Equal(t, int64(9), sent)
+		assert.Equal(t, int64(9), total)
+	})
+}
+
+func TestDownloadProgress(t *testing.T) {
+	payload := bytes.Repeat([]byte("%"), 50_000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	progress := &progressRecorder{}
+	var buf bytes.Buffer
+	require.NoError(t, newTestClient(t, server.URL).DownloadResult(server.URL, &buf, context.Background(), WithDownloadProgress(progress.record)))
+
+	sent, total := progress.last()
+	assert.Equal(t, int64(len(payload)), sent)
+	assert.Equal(t, int64(len(payload)), total)
+}
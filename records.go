@@ -0,0 +1,362 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Record is the render data of one document of a data source.
+// Nested objects are map[string]interface{} values.
+type Record map[string]interface{}
+
+// Field returns the value at a dot-separated path, like customer.address.city.
+func (r Record) Field(path string) (interface{}, bool) {
+	var value interface{} = map[string]interface{}(r)
+	for _, key := range strings.Split(path, ".") {
+		object, ok := asObject(value)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Set sets the value at a dot-separated path, like customer.address.city,
+// creating the intermediate objects. It fails if a parent of the path is not an object.
+func (r Record) Set(path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	object := map[string]interface{}(r)
+	for i, key := range keys[:len(keys)-1] {
+		child, exists := object[key]
+		if !exists {
+			created := make(map[string]interface{})
+			object[key] = created
+			object = created
+			continue
+		}
+		var ok bool
+		if object, ok = asObject(child); !ok {
+			return fmt.Errorf("cannot set field %s: %s is not an object", path, strings.Join(keys[:i+1], "."))
+		}
+	}
+	object[keys[len(keys)-1]] = value
+	return nil
+}
+
+// asObject returns value as a map, if it is a JSON object.
+func asObject(value interface{}) (map[string]interface{}, bool) {
+	switch object := value.(type) {
+	case map[string]interface{}:
+		return object, true
+	case Record:
+		return object, true
+	default:
+		return nil, false
+	}
+}
+
+// RecordSource reads the records of a data source, one per document.
+type RecordSource interface {
+	// Next returns the next record, or io.EOF once all records have been read.
+	Next() (Record, error)
+}
+
+// ReadRecords reads all the records of source.
+func ReadRecords(source RecordSource) ([]Record, error) {
+	var records []Record
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// CSVSource reads records from CSV data whose first row holds the column names.
+// Every other row is a record with its values as strings. Columns named with
+// dot-separated paths, like customer.address.city, are set as nested fields.
+// A row with more fields than the header is an error.
+type CSVSource struct {
+	// Reader is the underlying CSV reader. Configure it, for example its
+	// Comma, before reading the first record.
+	Reader *csv.Reader
+
+	columns []string
+	row     int
+}
+
+// NewCSVSource returns a RecordSource reading CSV data from r.
+func NewCSVSource(r io.Reader) *CSVSource {
+	return &CSVSource{Reader: csv.NewReader(r)}
+}
+
+// Next implements RecordSource.
+func (s *CSVSource) Next() (Record, error) {
+	if s.columns == nil {
+		columns, err := s.Reader.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV header: %w", err)
+		}
+		if len(columns) > 0 {
+			// Spreadsheet exports often start with a byte order mark.
+			columns[0] = strings.TrimPrefix(columns[0], "\ufeff")
+		}
+		s.columns = columns
+	}
+
+	values, err := s.Reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	s.row++
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV record %d: %w", s.row, err)
+	}
+	if len(values) > len(s.columns) {
+		return nil, fmt.Errorf("reading CSV record %d: too many fields", s.row)
+	}
+	record := make(Record, len(values))
+	for i, value := range values {
+		if err := record.Set(s.columns[i], value); err != nil {
+			return nil, fmt.Errorf("reading CSV record %d: %w", s.row, err)
+		}
+	}
+	return record, nil
+}
+
+// JSONLSource reads records from JSON Lines (NDJSON) data, where every line is a JSON object.
+type JSONLSource struct {
+	decoder *json.Decoder
+	record  int
+}
+
+// NewJSONLSource returns a RecordSource reading JSON Lines data from r.
+// Numbers are decoded as json.Number, so they keep their precision and format.
+func NewJSONLSource(r io.Reader) *JSONLSource {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &JSONLSource{decoder: decoder}
+}
+
+// Next implements RecordSource.
+func (s *JSONLSource) Next() (Record, error) {
+	var record Record
+	err := s.decoder.Decode(&record)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	s.record++
+	if err != nil {
+		return nil, fmt.Errorf("reading JSON record %d: %w", s.record, err)
+	}
+	if record == nil {
+		return nil, fmt.Errorf("reading JSON record %d: record is null", s.record)
+	}
+	return record, nil
+}
+
+// JSONArraySource reads records from a JSON array of objects.
+// The array is decoded one record at a time, so it does not need to fit in memory.
+type JSONArraySource struct {
+	decoder *json.Decoder
+	started bool
+	record  int
+}
+
+// NewJSONArraySource returns a RecordSource reading a JSON array from r.
+// Numbers are decoded as json.Number, so they keep their precision and format.
+func NewJSONArraySource(r io.Reader) *JSONArraySource {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	return &JSONArraySource{decoder: decoder}
+}
+
+// Next implements RecordSource.
+func (s *JSONArraySource) Next() (Record, error) {
+	if !s.started {
+		token, err := s.decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("reading JSON array: %w", err)
+		}
+		if token != json.Delim('[') {
+			return nil, fmt.Errorf("reading JSON array: expected an array, got %v", token)
+		}
+		s.started = true
+	}
+	if !s.decoder.More() {
+		return nil, io.EOF
+	}
+
+	s.record++
+	var record Record
+	if err := s.decoder.Decode(&record); err != nil {
+		return nil, fmt.Errorf("reading JSON record %d: %w", s.record, err)
+	}
+	if record == nil {
+		return nil, fmt.Errorf("reading JSON record %d: record is null", s.record)
+	}
+	return record, nil
+}
+
+// MapFields returns a RecordSource moving the fields of the records of source
+// to other paths. The keys of mapping are top-level fields, like the columns
+// of a CSV file, and its values are dot-separated paths like customer.address.city.
+// Fields missing from a record are skipped.
+func MapFields(source RecordSource, mapping map[string]string) RecordSource {
+	return &fieldMapper{source: source, mapping: mapping}
+}
+
+type fieldMapper struct {
+	source  RecordSource
+	mapping map[string]string
+}
+
+// Next implements RecordSource.
+func (m *fieldMapper) Next() (Record, error) {
+	record, err := m.source.Next()
+	if err != nil {
+		return nil, err
+	}
+	mapped := make(Record, len(record))
+	for key, value := range record {
+		if _, ok := m.mapping[key]; !ok {
+			mapped[key] = value
+		}
+	}
+	for key, path := range m.mapping {
+		value, ok := record[key]
+		if !ok {
+			continue
+		}
+		if err := mapped.Set(path, value); err != nil {
+			return nil, err
+		}
+	}
+	return mapped, nil
+}
+
+// placeholder matches the {field.path} placeholders of filename patterns.
+var placeholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// unsafeFilenameChars are replaced in the values substituted in filename patterns.
+var unsafeFilenameChars = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]`)
+
+// RecordFilename returns the filename of a record for a pattern such as
+// "invoices/{customer.id}-{number}.pdf". Placeholders are replaced by the record
+// fields at their path, except {index} which is replaced by the index of the record.
+// Characters not allowed in filenames are replaced by underscores in the values,
+// so they cannot point outside of the pattern's directory.
+func RecordFilename(pattern string, record Record, index int) (string, error) {
+	var missing []string
+	filename := placeholder.ReplaceAllStringFunc(pattern, func(match string) string {
+		path := strings.TrimSpace(match[1 : len(match)-1])
+		var value string
+		if path == "index" {
+			value = strconv.Itoa(index)
+		} else if field, ok := record.Field(path); ok && field != nil {
+			value = fmt.Sprint(field)
+		} else {
+			missing = append(missing, path)
+			return ""
+		}
+		value = strings.TrimSpace(unsafeFilenameChars.ReplaceAllString(value, "_"))
+		if value == "" || value == "." || value == ".." {
+			value = "_"
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("record %d has no %s field for filename pattern %q", index, strings.Join(missing, ", "), pattern)
+	}
+	return filename, nil
+}
+
+// RecordsOptions configure GenerateDocumentsFromRecords.
+type RecordsOptions struct {
+	BatchOptions
+	// OutputDir is the directory the documents are written to, created if needed.
+	// Defaults to the current directory.
+	OutputDir string
+	// FilenamePattern is the name of the file of every document, relative to OutputDir,
+	// as described by RecordFilename. Defaults to "{index}." followed by the render target.
+	FilenamePattern string
+}
+
+// RecordResult is the result of a document generated from a record.
+type RecordResult struct {
+	BatchResult
+	// Record is the record rendered.
+	Record Record
+	// Path is the path of the document file.
+	Path string
+}
+
+// GenerateDocumentsFromRecords generates a document for every record of source and writes it to a file,
+// like GenerateDocumentToFile. Each document is rendered with gdProps, typically naming
+// a TemplateId and a Target, and the record as its Data.
+// All records are read before rendering starts, so a malformed source or filename pattern fails early.
+// Documents are then generated concurrently like GenerateDocumentsBatch, returning the results
+// in record order and a *BatchError if any document failed.
+func (c *PogodocClient) GenerateDocumentsFromRecords(source RecordSource, gdProps GenerateDocumentProps, opts RecordsOptions, ctx context.Context) (_ []RecordResult, err error) {
+	ctx, span := c.startSpan(ctx, "GenerateDocumentsFromRecords", renderRequestAttributes(gdProps)...)
+	defer func() { endSpan(span, err) }()
+
+	records, err := ReadRecords(source)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attrBatchSize.Int(len(records)))
+
+	pattern := opts.FilenamePattern
+	if pattern == "" {
+		pattern = "{index}." + string(gdProps.InitializeRenderJobRequest.Target)
+	}
+	paths := make([]string, len(records))
+	indexes := make(map[string]int, len(records))
+	for i, record := range records {
+		filename, err := RecordFilename(pattern, record, i)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = filepath.Join(opts.OutputDir, filename)
+		if other, ok := indexes[paths[i]]; ok {
+			return nil, fmt.Errorf("records %d and %d would both be written to %s", other, i, paths[i])
+		}
+		indexes[paths[i]] = i
+	}
+
+	batch := c.runBatch(ctx, len(records), opts.BatchOptions, func(ctx context.Context, index int) (*GetJobStatusResponse, error) {
+		if err := os.MkdirAll(filepath.Dir(paths[index]), 0o755); err != nil {
+			return nil, fmt.Errorf("creating output directory: %w", err)
+		}
+		recordProps := gdProps
+		recordProps.Data = records[index]
+		return c.GenerateDocumentToFile(recordProps, paths[index], ctx, opts.PollOptions)
+	})
+	batchResults, err := collectBatch(ctx, batch, len(records))
+
+	results := make([]RecordResult, len(records))
+	for i, result := range batchResults {
+		results[i] = RecordResult{BatchResult: result, Record: records[i], Path: paths[i]}
+	}
+	return results, err
+}
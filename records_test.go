@@ -0,0 +1,183 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pogodoc/pogodoc-go/pogodoctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordSources(t *testing.T) {
+	expected := []Record{
+		{"id": "1", "customer": map[string]interface{}{"name": "Ada", "address": map[string]interface{}{"city": "London"}}},
+		{"id": "2", "customer": map[string]interface{}{"name": "Grace", "address": map[string]interface{}{"city": "New York"}}},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		source := NewCSVSource(strings.NewReader("\ufeffid,customer.name,customer.address.city\n1,Ada,London\n2,Grace,New York\n"))
+		records, err := ReadRecords(source)
+		require.NoError(t, err)
+		assert.Equal(t, expected, records)
+	})
+
+	t.Run("CSV with a custom separator", func(t *testing.T) {
+		source := NewCSVSource(strings.NewReader("id;customer.name\n1;Ada\n"))
+		source.Reader.Comma = ';'
+		records, err := ReadRecords(source)
+		require.NoError(t, err)
+		assert.Equal(t, []Record{{"id": "1", "customer": map[string]interface{}{"name": "Ada"}}}, records)
+	})
+
+	t.Run("CSV with conflicting columns", func(t *testing.T) {
+		_, err := ReadRecords(NewCSVSource(strings.NewReader("customer,customer.name\nAda,Ada\n")))
+		assert.ErrorContains(t, err, "CSV record 1: cannot set field customer.name: customer is not an object")
+	})
+
+	t.Run("CSV with a record wider than the header", func(t *testing.T) {
+		source := NewCSVSource(strings.NewReader("id,customer.name\n1,Ada\n2,Grace,extra\n"))
+		source.Reader.FieldsPerRecord = -1
+		records, err := ReadRecords(source)
+		assert.EqualError(t, err, "reading CSV record 2: too many fields")
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("JSON Lines", func(t *testing.T) {
+		source := NewJSONLSource(strings.NewReader(`{"id":"1","customer":{"name":"Ada","address":{"city":"London"}}}
+
+{"id":"2","customer":{"name":"Grace","address":{"city":"New York"}}}
+`))
+		records, err := ReadRecords(source)
+		require.NoError(t, err)
+		assert.Equal(t, expected, records)
+	})
+
+	t.Run("JSON Lines with an invalid record", func(t *testing.T) {
+		records, err := ReadRecords(NewJSONLSource(strings.NewReader("{\"id\":1}\n[1]\n")))
+		assert.ErrorContains(t, err, "reading JSON record 2")
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("JSON array", func(t *testing.T) {
+		source := NewJSONArraySource(strings.NewReader(`[
+			{"id":"1","customer":{"name":"Ada","address":{"city":"London"}}},
+			{"id":"2","customer":{"name":"Grace","address":{"city":"New York"}}}
+		]`))
+		records, err := ReadRecords(source)
+		require.NoError(t, err)
+		assert.Equal(t, expected, records)
+	})
+
+	t.Run("JSON numbers", func(t *testing.T) {
+		sources := map[string]RecordSource{
+			"JSON Lines": NewJSONLSource(strings.NewReader(`{"invoice":{"id":1234567,"total":9007199254740993}}`)),
+			"JSON array": NewJSONArraySource(strings.NewReader(`[{"invoice":{"id":1234567,"total":9007199254740993}}]`)),
+		}
+		for name, source := range sources {
+			records, err := ReadRecords(source)
+			require.NoError(t, err, name)
+			require.Len(t, records, 1, name)
+
+			filename, err := RecordFilename("{invoice.id}.pdf", records[0], 1)
+			require.NoError(t, err, name)
+			assert.Equal(t, "1234567.pdf", filename, name)
+
+			data, err := json.Marshal(records[0])
+			require.NoError(t, err, name)
+			assert.JSONEq(t, `{"invoice":{"id":1234567,"total":9007199254740993}}`, string(data), name)
+			assert.Contains(t, string(data), "9007199254740993", name)
+		}
+	})
+
+	t.Run("JSON array of an object", func(t *testing.T) {
+		_, err := ReadRecords(NewJSONArraySource(strings.NewReader(`{"id":"1"}`)))
+		assert.ErrorContains(t, err, "expected an array")
+	})
+
+	t.Run("mapped fields", func(t *testing.T) {
+		source := MapFields(
+			NewCSVSource(strings.NewReader("Invoice,Customer,City\n1,Ada,London\n2,Grace,New York\n")),
+			map[string]string{"Invoice": "id", "Customer": "customer.name", "City": "customer.address.city"},
+		)
+		records, err := ReadRecords(source)
+		require.NoError(t, err)
+		assert.Equal(t, expected, records)
+	})
+}
+
+func TestRecordFilename(t *testing.T) {
+	record := Record{"id": float64(42), "customer": map[string]interface{}{"name": "../Ada: Lovelace"}}
+
+	filename, err := RecordFilename("{customer.name}/invoice-{id}-{index}.pdf", record, 3)
+	require.NoError(t, err)
+	assert.Equal(t, ".._Ada_ Lovelace/invoice-42-3.pdf", filename)
+
+	_, err = RecordFilename("{customer.email}-{id}.pdf", record, 3)
+	assert.EqualError(t, err, `record 3 has no customer.email field for filename pattern "{customer.email}-{id}.pdf"`)
+}
+
+func TestGenerateDocumentsFromRecords(t *testing.T) {
+	server := pogodoctest.NewServer()
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+	templateId := server.AddTemplate(pogodoctest.Template{IndexHtml: "<h1>Invoice</h1>"})
+	gdProps := GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			Type:       InitializeRenderJobRequestTypeHtml,
+			Target:     InitializeRenderJobRequestTargetPdf,
+			TemplateId: String(templateId),
+		},
+	}
+	opts := RecordsOptions{
+		BatchOptions: BatchOptions{
+			Concurrency: 2,
+			PollOptions: PollOptions{InitialDelay: -1, Interval: time.Millisecond},
+		},
+		OutputDir:       filepath.Join(t.TempDir(), "invoices"),
+		FilenamePattern: "{customer.address.country}/invoice-{id}.pdf",
+	}
+	csv := "id,customer.name,customer.address.country\n1,Ada,UK\n2,Grace,US\n3,Alan,UK\n"
+
+	results, err := client.GenerateDocumentsFromRecords(NewCSVSource(strings.NewReader(csv)), gdProps, opts, context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	for i, path := range []string{"UK/invoice-1.pdf", "US/invoice-2.pdf", "UK/invoice-3.pdf"} {
+		result := results[i]
+		require.NoError(t, result.Err)
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, filepath.Join(opts.OutputDir, path), result.Path)
+
+		output, err := os.ReadFile(result.Path)
+		require.NoError(t, err)
+		var rendered map[string]interface{}
+		require.NoError(t, json.Unmarshal(output, &rendered))
+		assert.Equal(t, templateId, rendered["templateId"])
+		assert.Equal(t, map[string]interface{}(result.Record), rendered["data"])
+	}
+
+	t.Run("default filenames", func(t *testing.T) {
+		opts := opts
+		opts.OutputDir = t.TempDir()
+		opts.FilenamePattern = ""
+		results, err := client.GenerateDocumentsFromRecords(NewJSONLSource(strings.NewReader(`{"id":1}`)), gdProps, opts, context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(opts.OutputDir, "0.pdf"), results[0].Path)
+		assert.FileExists(t, results[0].Path)
+	})
+
+	t.Run("duplicate filenames fail before rendering", func(t *testing.T) {
+		jobs := server.Jobs()
+		opts := opts
+		opts.FilenamePattern = "{customer.address.country}.pdf"
+		_, err := client.GenerateDocumentsFromRecords(NewCSVSource(strings.NewReader(csv)), gdProps, opts, context.Background())
+		assert.ErrorContains(t, err, "records 0 and 2 would both be written to")
+		assert.Equal(t, jobs, server.Jobs())
+	})
+}
@@ -0,0 +1,154 @@
+package pogodoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrJobNotFinished is returned by RenderJob.Result when the job has not been
+// observed in a terminal state yet.
+var ErrJobNotFinished = errors.New("pogodoc: render job has not finished")
+
+// JobEvent is emitted by RenderJob.Watch whenever the job status changes.
+// The last event sent before the channel is closed is either terminal or carries Err.
+type JobEvent struct {
+	Status   JobStatus
+	Response *GetJobStatusResponse
+	Err      error
+}
+
+// RenderJob is a handle to an asynchronous render job.
+// It is safe for concurrent use, and can be persisted by its ID and
+// recreated later with PogodocClient.RenderJob.
+type RenderJob struct {
+	client *PogodocClient
+	jobId  string
+
+	mu         sync.Mutex
+	lastStatus *GetJobStatusResponse
+}
+
+// RenderJob returns a handle to an existing render job with the given ID.
+func (c *PogodocClient) RenderJob(jobId string) *RenderJob {
+	return &RenderJob{
+		client: c,
+		jobId:  jobId,
+	}
+}
+
+// StartGenerateDocumentJob starts an asynchronous document generation job like StartGenerateDocument,
+// but returns a *RenderJob handle instead of the bare job ID.
+func (c *PogodocClient) StartGenerateDocumentJob(gdProps GenerateDocumentProps, ctx context.Context) (*RenderJob, error) {
+	jobId, err := c.StartGenerateDocument(gdProps, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.RenderJob(*jobId), nil
+}
+
+// ID returns the ID of the render job.
+func (j *RenderJob) ID() string {
+	return j.jobId
+}
+
+// Status fetches the current status of the job from the Pogodoc API.
+func (j *RenderJob) Status(ctx context.Context) (*GetJobStatusResponse, error) {
+	jobStatus, err := j.client.documents().GetJobStatus(ctx, j.jobId)
+	if err != nil {
+		return nil, &StepError{Step: StepGetJobStatus, Err: err}
+	}
+	j.observe(jobStatus)
+	return jobStatus, nil
+}
+
+// Wait polls the job until it reaches a terminal state, as PollForJobCompletion does.
+func (j *RenderJob) Wait(ctx context.Context, opts ...PollOptions) (*GetJobStatusResponse, error) {
+	pollOpts, err := resolvePollOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return j.client.pollJob(ctx, j.jobId, pollOpts, j.observe)
+}
+
+// Watch polls the job in the background and sends a JobEvent every time its status changes.
+// The channel is closed once the job reaches a terminal state, polling fails or ctx is done.
+func (j *RenderJob) Watch(ctx context.Context, opts ...PollOptions) <-chan JobEvent {
+	events := make(chan JobEvent, 1)
+	send := func(event JobEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	pollOpts, err := resolvePollOptions(opts)
+	if err != nil {
+		events <- JobEvent{Err: err}
+		close(events)
+		return events
+	}
+
+	go func() {
+		defer close(events)
+
+		var previous JobStatus
+		_, err := j.client.pollJob(ctx, j.jobId, pollOpts, func(jobStatus *GetJobStatusResponse) {
+			j.observe(jobStatus)
+			if status := StatusOf(jobStatus); status != previous {
+				previous = status
+				send(JobEvent{Status: status, Response: jobStatus})
+			}
+		})
+		var renderErr *RenderJobError
+		if err != nil && !errors.As(err, &renderErr) {
+			send(JobEvent{Status: previous, Err: err})
+		}
+	}()
+
+	return events
+}
+
+// Result returns the final status of the job once it has been observed in a terminal state
+// by Status, Wait or Watch. It returns ErrJobNotFinished if the job is still running,
+// and a *RenderJobError alongside the status if the job failed.
+func (j *RenderJob) Result() (*GetJobStatusResponse, error) {
+	j.mu.Lock()
+	jobStatus := j.lastStatus
+	j.mu.Unlock()
+
+	switch StatusOf(jobStatus) {
+	case JobStatusDone:
+		return jobStatus, nil
+	case JobStatusFailed:
+		return jobStatus, newRenderJobError(jobStatus)
+	default:
+		return nil, ErrJobNotFinished
+	}
+}
+
+// Download writes the rendered output of the job to w.
+// If the job has not finished yet, Download waits for it with the default PollOptions.
+func (j *RenderJob) Download(ctx context.Context, w io.Writer, opts ...DownloadOption) error {
+	jobStatus, err := j.Result()
+	if errors.Is(err, ErrJobNotFinished) {
+		jobStatus, err = j.Wait(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if jobStatus.Output == nil || jobStatus.Output.Data == nil || jobStatus.Output.Data.Url == "" {
+		return fmt.Errorf("job %s has no output", j.jobId)
+	}
+
+	return j.client.DownloadResult(jobStatus.Output.Data.Url, w, ctx, opts...)
+}
+
+// observe records the latest status seen for the job.
+func (j *RenderJob) observe(jobStatus *GetJobStatusResponse) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.lastStatus = jobStatus
+}
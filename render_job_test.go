@@ -0,0 +1,104 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRenderJobServer(t *testing.T, pendingPolls int32) *httptest.Server {
+	t.Helper()
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("GET /jobs/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		jobStatus := GetJobStatusResponse{JobId: r.PathValue("jobId"), Target: "pdf", Status: "in-progress"}
+		if calls.Add(1) > pendingPolls {
+			jobStatus.Status = "done"
+			jobStatus.Success = Bool(true)
+			jobStatus.Output = &GetJobStatusResponseOutput{
+				Data: &GetJobStatusResponseOutputData{Url: server.URL + "/output/" + jobStatus.JobId},
+			}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(jobStatus))
+	})
+	mux.HandleFunc("GET /output/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("%PDF-" + r.PathValue("jobId")))
+	})
+	return server
+}
+
+func TestRenderJob(t *testing.T) {
+	fastPoll := PollOptions{InitialDelay: -1, Interval: time.Millisecond}
+
+	t.Run("Result reports an unfinished job", func(t *testing.T) {
+		server := newRenderJobServer(t, 1)
+		job := newTestClient(t, server.URL).RenderJob("job-1")
+
+		_, err := job.Result()
+		require.ErrorIs(t, err, ErrJobNotFinished)
+
+		jobStatus, err := job.Status(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "in-progress", jobStatus.Status)
+
+		_, err = job.Result()
+		require.ErrorIs(t, err, ErrJobNotFinished)
+	})
+
+	t.Run("Wait records the result and Download fetches the output", func(t *testing.T) {
+		server := newRenderJobServer(t, 2)
+		job := newTestClient(t, server.URL).RenderJob("job-2")
+		assert.Equal(t, "job-2", job.ID())
+
+		jobStatus, err := job.Wait(context.Background(), fastPoll)
+		require.NoError(t, err)
+		assert.Equal(t, "done", jobStatus.Status)
+
+		result, err := job.Result()
+		require.NoError(t, err)
+		assert.Same(t, jobStatus, result)
+
+		var buf bytes.Buffer
+		require.NoError(t, job.Download(context.Background(), &buf))
+		assert.Equal(t, "%PDF-job-2", buf.String())
+	})
+
+	t.Run("Watch emits status changes until the job is done", func(t *testing.T) {
+		server := newRenderJobServer(t, 3)
+		job := newTestClient(t, server.URL).RenderJob("job-3")
+
+		var statuses []JobStatus
+		for event := range job.Watch(context.Background(), fastPoll) {
+			require.NoError(t, event.Err)
+			statuses = append(statuses, event.Status)
+		}
+		assert.Equal(t, []JobStatus{JobStatusInProgress, JobStatusDone}, statuses)
+
+		_, err := job.Result()
+		require.NoError(t, err)
+	})
+
+	t.Run("Watch reports timeouts as an error event", func(t *testing.T) {
+		server := newRenderJobServer(t, 100)
+		job := newTestClient(t, server.URL).RenderJob("job-4")
+
+		opts := fastPoll
+		opts.MaxAttempts = 2
+		var last JobEvent
+		for event := range job.Watch(context.Background(), opts) {
+			last = event
+		}
+		require.ErrorIs(t, last.Err, ErrJobTimeout)
+	})
+}
@@ -0,0 +1,348 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 2
+	defaultMaxRetryWait  = time.Minute
+	minRetryDelay        = 500 * time.Millisecond
+	maxRetryDelay        = 5000 * time.Millisecond
+
+	retryAfterHeader     = "Retry-After"
+	rateLimitResetHeader = "X-RateLimit-Reset"
+
+	// rateLimitResetEpoch separates X-RateLimit-Reset values given as a Unix
+	// timestamp from values given as a number of seconds to wait.
+	rateLimitResetEpoch = 1_000_000_000
+)
+
+// DefaultIdempotentMethods are the HTTP methods DefaultRetryPolicy retries
+// after a network error, as defined by RFC 9110.
+var DefaultIdempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+// IdempotencyKeyHeader marks a request as safe to retry after a network error,
+// regardless of its method.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryOptions configures how failed requests are retried. Zero values
+// select the defaults documented on each field.
+type RetryOptions struct {
+	// BaseDelay is the delay before the first retry, from which later retries
+	// back off quadratically, waiting BaseDelay × (1 + retries²). No retry
+	// waits less than it. Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps the back-off. Defaults to 5s.
+	MaxDelay time.Duration
+	// Jitter randomizes the back-off. Defaults to DefaultJitter.
+	Jitter JitterStrategy
+	// MaxWait is the longest delay requested by the server through the
+	// Retry-After or X-RateLimit-Reset headers that the client is willing to
+	// wait. Responses asking for a longer delay are not retried. Defaults to 1m.
+	MaxWait time.Duration
+	// Budget limits the total time spent retrying a call, measured from the
+	// start of the first attempt: a retry that would not start before the
+	// budget runs out is not issued. Unlimited by default.
+	Budget time.Duration
+	// StatusCodes replaces the response status codes retried by
+	// DefaultRetryPolicy. Defaults to 408, 429 and 5xx.
+	StatusCodes []int
+	// Policy decides which failed attempts are retried. Defaults to
+	// DefaultRetryPolicy, configured with StatusCodes.
+	Policy RetryPolicy
+	// Hook is called before every retry.
+	Hook RetryHook
+}
+
+// JitterStrategy randomizes the back-off delay between retries.
+type JitterStrategy func(delay time.Duration) time.Duration
+
+// DefaultJitter randomizes the delay in the range of 75%-100% of its value.
+func DefaultJitter(delay time.Duration) time.Duration {
+	return delay - randDuration(delay/4)
+}
+
+// NoJitter uses the back-off delay as-is.
+func NoJitter(delay time.Duration) time.Duration {
+	return delay
+}
+
+// FullJitter randomizes the delay in the range of 0%-100% of its value.
+// The result is still raised to the base delay.
+func FullJitter(delay time.Duration) time.Duration {
+	return randDuration(delay)
+}
+
+// randDuration returns a random duration in [0, n].
+func randDuration(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return rand.N(n + 1)
+}
+
+// RetryEvent describes a retry that is about to happen.
+type RetryEvent struct {
+	// Attempt is the number of the attempt that failed, starting at 1.
+	Attempt uint
+	// Delay is how long the client waits before the next attempt. It honors
+	// the Retry-After and X-RateLimit-Reset response headers, if any.
+	Delay time.Duration
+	// Request is the request being retried.
+	Request *http.Request
+	// Response is the response that triggered the retry, if any.
+	Response *http.Response
+	// Err is the network error that triggered the retry, if any.
+	Err error
+}
+
+// RetryHook is called before a failed request is retried.
+type RetryHook func(RetryEvent)
+
+// RetryPolicy decides whether a failed attempt is retried.
+type RetryPolicy interface {
+	// ShouldRetry reports whether the request should be retried after an attempt
+	// returned the given response, or the given error if no response was received.
+	ShouldRetry(request *http.Request, response *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy retries 408, 429 and 5xx responses, and retries idempotent
+// requests after transient network errors.
+type DefaultRetryPolicy struct {
+	// StatusCodes lists the response status codes to retry. It defaults to
+	// 408, 429 and every 5xx status code.
+	StatusCodes []int
+	// IdempotentMethods lists the HTTP methods retried after a network error.
+	// It defaults to DefaultIdempotentMethods. Requests carrying an
+	// Idempotency-Key header are retried regardless of their method.
+	IdempotentMethods []string
+}
+
+// ShouldRetry implements RetryPolicy.
+func (p DefaultRetryPolicy) ShouldRetry(request *http.Request, response *http.Response, err error) bool {
+	if err != nil {
+		return p.isIdempotent(request) && IsTransientError(err)
+	}
+	if p.StatusCodes != nil {
+		return slices.Contains(p.StatusCodes, response.StatusCode)
+	}
+	return response.StatusCode == http.StatusTooManyRequests ||
+		response.StatusCode == http.StatusRequestTimeout ||
+		response.StatusCode >= http.StatusInternalServerError
+}
+
+func (p DefaultRetryPolicy) isIdempotent(request *http.Request) bool {
+	if request.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	methods := p.IdempotentMethods
+	if methods == nil {
+		methods = DefaultIdempotentMethods
+	}
+	return slices.Contains(methods, request.Method)
+}
+
+// IsTransientError reports whether err is a network error that is likely to go
+// away on its own, such as a timeout, a reset connection or a truncated response.
+// Cancelled and expired contexts are never transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryRequest issues req with do and, upon failure, retries it as long as the retry
+// policy allows and attempts remain, with a quadratic back-off between
+// attempts unless the server specifies the delay with a Retry-After or
+// X-RateLimit-Reset header. The last response is returned as-is.
+//
+// The request body is rewound before every retry with GetBody; requests
+// without GetBody have their body buffered in memory before the first attempt.
+func (c *config) retryRequest(do func(*http.Request) (*http.Response, error), req *http.Request) (*http.Response, error) {
+	attempts := c.attempts()
+	retry := c.retryOptions()
+	var deadline time.Time
+	if retry.Budget > 0 {
+		deadline = time.Now().Add(retry.Budget)
+	}
+	if attempts > 1 {
+		if err := bufferRequestBody(req); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := uint(0); ; attempt++ {
+		// If the call has been cancelled, don't issue the request.
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		// The previous attempt consumed the body, so send a fresh copy.
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewinding request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		resp, err := do(req)
+		if !retry.Policy.ShouldRetry(req, resp, err) {
+			return resp, err
+		}
+		// A cancelled call surfaces as a transport error; don't retry it.
+		if err != nil && req.Context().Err() != nil {
+			return nil, err
+		}
+		// Don't wait for a retry that will never be issued.
+		if attempt+1 >= attempts {
+			return resp, err
+		}
+
+		var (
+			delay time.Duration
+			ok    bool
+		)
+		if resp != nil {
+			delay, ok = serverRetryDelay(resp, time.Now())
+			if ok && delay > retry.MaxWait {
+				// The server asked us to back off for longer than we are
+				// willing to wait, so surface the response right away.
+				return resp, nil
+			}
+		}
+		if !ok {
+			delay = retryDelay(attempt, &retry)
+		}
+		// Don't start a retry once the retry budget is spent.
+		if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+
+		event := RetryEvent{
+			Attempt:  attempt + 1,
+			Delay:    delay,
+			Request:  req,
+			Response: resp,
+			Err:      err,
+		}
+		logRetry(c.logger, event)
+		if resp != nil {
+			c.metrics.IncRetry(resp.StatusCode)
+		} else {
+			c.metrics.IncRetry(0)
+		}
+		if retry.Hook != nil {
+			retry.Hook(event)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// bufferRequestBody reads a request body that cannot be rewound into memory,
+// so that it can be sent again on retries.
+func bufferRequestBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	payload, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(payload)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return nil
+}
+
+// serverRetryDelay returns the delay requested by the server through the
+// Retry-After header, given either in seconds or as an HTTP date, or, for
+// 429 responses, through the X-RateLimit-Reset header, given either as a
+// Unix timestamp or in seconds. It reports false if neither header is usable.
+func serverRetryDelay(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if value := strings.TrimSpace(resp.Header.Get(retryAfterHeader)); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return max(date.Sub(now), 0), true
+		}
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if value := strings.TrimSpace(resp.Header.Get(rateLimitResetHeader)); value != "" {
+		reset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || reset < 0 {
+			return 0, false
+		}
+		if reset >= rateLimitResetEpoch {
+			return max(time.Unix(reset, 0).Sub(now), 0), true
+		}
+		return time.Duration(reset) * time.Second, true
+	}
+	return 0, false
+}
+
+// retryDelay calculates the back-off delay based on the retry attempt.
+func retryDelay(attempt uint, retry *RetryOptions) time.Duration {
+	// Apply quadratic backoff.
+	delay := retry.BaseDelay + retry.BaseDelay*time.Duration(attempt*attempt)
+
+	// Do not allow the number to exceed the max delay.
+	if delay > retry.MaxDelay {
+		delay = retry.MaxDelay
+	}
+
+	// Apply some jitter by randomizing the value.
+	delay = retry.Jitter(delay)
+
+	// Never sleep less than the base delay.
+	if delay < retry.BaseDelay {
+		delay = retry.BaseDelay
+	}
+
+	return delay
+}
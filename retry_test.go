@@ -0,0 +1,399 @@
+package pogodoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTransport returns a transport sending requests with client,
+// configured with the given options.
+func newTestTransport(client HTTPClient, opts ...RequestOption) *transport {
+	sdkOpts, _ := splitOptions(opts)
+	return &transport{client: client, config: newConfig(sdkOpts)}
+}
+
+// doRequest sends a request through the transport and closes the response body.
+func doRequest(ctx context.Context, t *transport, method string, url string, body io.Reader, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := t.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description string
+		statusCode  int
+		header      http.Header
+		wantDelay   time.Duration
+		wantOK      bool
+	}{
+		{
+			description: "Retry-After in seconds",
+			statusCode:  http.StatusServiceUnavailable,
+			header:      http.Header{"Retry-After": {"3"}},
+			wantDelay:   3 * time.Second,
+			wantOK:      true,
+		},
+		{
+			description: "Retry-After as an HTTP date",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{"Retry-After": {now.Add(10 * time.Second).Format(http.TimeFormat)}},
+			wantDelay:   10 * time.Second,
+			wantOK:      true,
+		},
+		{
+			description: "Retry-After date in the past",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{"Retry-After": {now.Add(-time.Minute).Format(http.TimeFormat)}},
+			wantDelay:   0,
+			wantOK:      true,
+		},
+		{
+			description: "X-RateLimit-Reset as a Unix timestamp",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{"X-Ratelimit-Reset": {"1709294405"}},
+			wantDelay:   5 * time.Second,
+			wantOK:      true,
+		},
+		{
+			description: "X-RateLimit-Reset in seconds",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{"X-Ratelimit-Reset": {"2"}},
+			wantDelay:   2 * time.Second,
+			wantOK:      true,
+		},
+		{
+			description: "Retry-After takes precedence over X-RateLimit-Reset",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{"Retry-After": {"1"}, "X-Ratelimit-Reset": {"2"}},
+			wantDelay:   time.Second,
+			wantOK:      true,
+		},
+		{
+			description: "X-RateLimit-Reset is ignored outside of 429 responses",
+			statusCode:  http.StatusServiceUnavailable,
+			header:      http.Header{"X-Ratelimit-Reset": {"2"}},
+		},
+		{
+			description: "invalid Retry-After",
+			statusCode:  http.StatusServiceUnavailable,
+			header:      http.Header{"Retry-After": {"soon"}},
+		},
+		{
+			description: "no headers",
+			statusCode:  http.StatusTooManyRequests,
+			header:      http.Header{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			delay, ok := serverRetryDelay(&http.Response{StatusCode: tc.statusCode, Header: tc.header}, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantDelay, delay)
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	newServer := func(retryAfter string) (*httptest.Server, *atomic.Int32) {
+		calls := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	t.Run("waits for the requested delay and reports it to the hook", func(t *testing.T) {
+		server, calls := newServer("0")
+
+		var events []RetryEvent
+		transport := newTestTransport(server.Client(), WithRetryHook(func(event RetryEvent) {
+			events = append(events, event)
+		}))
+		start := time.Now()
+		err := doRequest(context.Background(), transport, http.MethodGet, server.URL, nil, nil)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), minRetryDelay)
+		assert.Equal(t, int32(2), calls.Load())
+		require.Len(t, events, 1)
+		assert.Equal(t, uint(1), events[0].Attempt)
+		assert.Equal(t, time.Duration(0), events[0].Delay)
+		assert.Equal(t, http.StatusTooManyRequests, events[0].Response.StatusCode)
+	})
+
+	t.Run("does not retry when the requested delay exceeds the max wait", func(t *testing.T) {
+		server, calls := newServer("120")
+
+		transport := newTestTransport(server.Client(), WithMaxRetryWait(time.Second))
+		err := doRequest(context.Background(), transport, http.MethodGet, server.URL, nil, nil)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestRetryReplaysRequestBody(t *testing.T) {
+	tests := []struct {
+		description string
+		giveBody    func() io.Reader
+	}{
+		{
+			description: "request body with GetBody",
+			giveBody:    func() io.Reader { return bytes.NewReader([]byte(`{"id":"1"}`)) },
+		},
+		{
+			description: "request body without GetBody",
+			giveBody:    func() io.Reader { return io.MultiReader(strings.NewReader(`{"id":"1"}`)) },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				bodies = append(bodies, string(body))
+				if len(bodies) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			err := doRequest(context.Background(), newTestTransport(server.Client()), http.MethodPost, server.URL, tc.giveBody(), nil)
+			require.NoError(t, err)
+			require.Len(t, bodies, 2)
+			assert.JSONEq(t, `{"id":"1"}`, bodies[0])
+			assert.Equal(t, bodies[0], bodies[1])
+		})
+	}
+}
+
+func TestRetryStopsWaitingWhenContextIsDone(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	transport := newTestTransport(server.Client())
+	transport.config.maxAttempts = 3
+	start := time.Now()
+	err := doRequest(ctx, transport, http.MethodGet, server.URL, nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	newServer := func(t *testing.T) (*httptest.Server, *atomic.Int32) {
+		calls := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				// Drop the connection without responding.
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if !assert.NoError(t, err) {
+					return
+				}
+				conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	tests := []struct {
+		description string
+		method      string
+		header      http.Header
+		policy      RetryPolicy
+		wantCalls   int32
+		wantError   bool
+	}{
+		{
+			description: "idempotent requests are retried",
+			method:      http.MethodGet,
+			wantCalls:   2,
+		},
+		{
+			description: "non-idempotent requests are not retried",
+			method:      http.MethodPost,
+			wantCalls:   1,
+			wantError:   true,
+		},
+		{
+			description: "requests with an idempotency key are retried",
+			method:      http.MethodPost,
+			header:      http.Header{IdempotencyKeyHeader: {"key"}},
+			wantCalls:   2,
+		},
+		{
+			description: "idempotent methods are configurable",
+			method:      http.MethodPost,
+			policy:      DefaultRetryPolicy{IdempotentMethods: []string{http.MethodPost}},
+			wantCalls:   2,
+		},
+		{
+			description: "custom policies replace the default one",
+			method:      http.MethodGet,
+			policy:      retryPolicyFunc(func(*http.Request, *http.Response, error) bool { return false }),
+			wantCalls:   1,
+			wantError:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			server, calls := newServer(t)
+
+			err := doRequest(context.Background(), newTestTransport(server.Client(), WithRetryPolicy(tc.policy)), tc.method, server.URL, nil, tc.header)
+			if tc.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(io.ErrUnexpectedEOF))
+	assert.True(t, IsTransientError(fmt.Errorf("reading body: %w", syscall.ECONNRESET)))
+	assert.True(t, IsTransientError(&net.DNSError{IsTimeout: true}))
+	assert.False(t, IsTransientError(&net.DNSError{IsNotFound: true}))
+	assert.False(t, IsTransientError(context.Canceled))
+	assert.False(t, IsTransientError(errors.New("invalid request")))
+}
+
+type retryPolicyFunc func(*http.Request, *http.Response, error) bool
+
+func (f retryPolicyFunc) ShouldRetry(request *http.Request, response *http.Response, err error) bool {
+	return f(request, response, err)
+}
+
+func TestRetryDelay(t *testing.T) {
+	retry := &RetryOptions{
+		BaseDelay: 10 * time.Millisecond,
+		MaxDelay:  100 * time.Millisecond,
+		Jitter:    NoJitter,
+	}
+	assert.Equal(t, 10*time.Millisecond, retryDelay(0, retry))
+	assert.Equal(t, 20*time.Millisecond, retryDelay(1, retry))
+	assert.Equal(t, 50*time.Millisecond, retryDelay(2, retry))
+	assert.Equal(t, 100*time.Millisecond, retryDelay(3, retry))
+
+	retry.Jitter = FullJitter
+	for i := 0; i < 100; i++ {
+		delay := retryDelay(3, retry)
+		assert.GreaterOrEqual(t, delay, retry.BaseDelay)
+		assert.LessOrEqual(t, delay, retry.MaxDelay)
+	}
+}
+
+func TestRetryOptions(t *testing.T) {
+	newServer := func(t *testing.T, statusCode int) (*httptest.Server, *atomic.Int32) {
+		calls := new(atomic.Int32)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(statusCode)
+		}))
+		t.Cleanup(server.Close)
+		return server, calls
+	}
+
+	t.Run("per-call options override client-wide options", func(t *testing.T) {
+		server, calls := newServer(t, http.StatusConflict)
+
+		var delays []time.Duration
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token",
+			WithRetryBaseDelay(time.Millisecond),
+			WithRetryJitter(NoJitter),
+			WithRetryableStatusCodes(http.StatusServiceUnavailable),
+			WithRetryHook(func(event RetryEvent) {
+				delays = append(delays, event.Delay)
+			}),
+		)
+		require.NoError(t, err)
+
+		_, err = client.Documents.GetJobStatus(context.Background(), "job",
+			WithMaxAttempts(3), WithRetryableStatusCodes(http.StatusConflict))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, delays)
+	})
+
+	t.Run("the generated client does not retry again", func(t *testing.T) {
+		server, calls := newServer(t, http.StatusServiceUnavailable)
+
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithMaxAttempts(3), WithRetryBaseDelay(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = client.Documents.GetJobStatus(context.Background(), "job")
+		assert.Error(t, err)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("retries stop once the budget is spent", func(t *testing.T) {
+		server, calls := newServer(t, http.StatusServiceUnavailable)
+
+		transport := newTestTransport(server.Client(),
+			WithRetryBaseDelay(20*time.Millisecond),
+			WithRetryMaxDelay(20*time.Millisecond),
+			WithRetryJitter(NoJitter),
+			WithRetryBudget(50*time.Millisecond),
+		)
+		transport.config.maxAttempts = 10
+		start := time.Now()
+		err := doRequest(context.Background(), transport, http.MethodGet, server.URL, nil, nil)
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+}
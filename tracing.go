@@ -0,0 +1,110 @@
+package pogodoc
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by the SDK.
+const tracerName = "github.com/Pogodoc/pogodoc-go"
+
+// Span attributes recorded by the SDK.
+const (
+	attrJobId       = attribute.Key("pogodoc.job.id")
+	attrJobStatus   = attribute.Key("pogodoc.job.status")
+	attrTemplateId  = attribute.Key("pogodoc.template.id")
+	attrTarget      = attribute.Key("pogodoc.render.target")
+	attrRenderTime  = attribute.Key("pogodoc.render.time")
+	attrPollAttempt = attribute.Key("pogodoc.poll.attempt")
+)
+
+// tracer returns the tracer of the configured tracer provider,
+// falling back to the global one.
+func (c *PogodocClient) tracer() trace.Tracer {
+	tracerProvider := c.config().tracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	return tracerProvider.Tracer(tracerName)
+}
+
+// startSpan starts a span for a PogodocClient method.
+func (c *PogodocClient) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer().Start(ctx, "pogodoc."+method, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// renderRequestAttributes describes the template and target of a render request.
+func renderRequestAttributes(gdProps GenerateDocumentProps) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrTarget.String(string(gdProps.InitializeRenderJobRequest.Target)),
+	}
+	if templateId := gdProps.InitializeRenderJobRequest.TemplateId; templateId != nil {
+		attrs = append(attrs, attrTemplateId.String(*templateId))
+	}
+	return attrs
+}
+
+// jobStatusAttributes describes a job status: its ID, template, target and,
+// once rendered, the render time reported by the server.
+func jobStatusAttributes(jobStatus *GetJobStatusResponse) []attribute.KeyValue {
+	if jobStatus == nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{
+		attrJobId.String(jobStatus.JobId),
+		attrJobStatus.String(string(StatusOf(jobStatus))),
+		attrTarget.String(jobStatus.Target),
+	}
+	if jobStatus.TemplateId != nil {
+		attrs = append(attrs, attrTemplateId.String(*jobStatus.TemplateId))
+	}
+	if jobStatus.Output != nil && jobStatus.Output.Metadata != nil {
+		attrs = append(attrs, attrRenderTime.Float64(jobStatus.Output.Metadata.RenderTime))
+	}
+	return attrs
+}
+
+// traceMiddleware creates a client span for every HTTP request. URLs are
+// recorded without their query string, so presigned URL signatures are not leaked.
+func (c *PogodocClient) traceMiddleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		url := *req.URL
+		url.RawQuery = ""
+		url.User = nil
+		ctx, span := c.tracer().Start(req.Context(), req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.full", url.String()),
+				attribute.String("server.address", req.URL.Hostname()),
+			),
+		)
+		defer span.End()
+
+		resp, err := next.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return resp, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		return resp, nil
+	})
+}
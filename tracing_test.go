@@ -0,0 +1,146 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var polls int
+	mux.HandleFunc("POST /documents/init", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(InitializeRenderJobResponse{
+			JobId:                  "job",
+			PresignedDataUploadUrl: String("http://" + r.Host + "/upload?X-Amz-Signature=secret"),
+		}))
+	})
+	mux.HandleFunc("PUT /upload", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("POST /documents/{jobId}/render", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(StartRenderJobResponse{JobId: r.PathValue("jobId")}))
+	})
+	mux.HandleFunc("GET /jobs/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		jobStatus := GetJobStatusResponse{JobId: r.PathValue("jobId"), Target: "pdf", TemplateId: String("template"), Status: "in-progress"}
+		if polls > 1 {
+			jobStatus.Status = "done"
+			jobStatus.Output = &GetJobStatusResponseOutput{
+				Metadata: &GetJobStatusResponseOutputMetadata{RenderTime: 1.5},
+			}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(jobStatus))
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithTracerProvider(tracerProvider))
+	require.NoError(t, err)
+
+	_, err = client.GenerateDocument(GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			Type:       InitializeRenderJobRequestTypeHtml,
+			Target:     InitializeRenderJobRequestTargetPdf,
+			TemplateId: String("template"),
+		},
+	}, context.Background(), PollOptions{InitialDelay: -1, Interval: time.Millisecond})
+	require.NoError(t, err)
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+	require.Len(t, spans["pogodoc.GenerateDocument"], 1)
+	root := spans["pogodoc.GenerateDocument"][0]
+	assert.False(t, root.Parent().IsValid())
+	assert.Contains(t, root.Attributes(), attrTemplateId.String("template"))
+
+	require.Len(t, spans["pogodoc.StartGenerateDocument"], 1)
+	start := spans["pogodoc.StartGenerateDocument"][0]
+	assert.Equal(t, root.SpanContext().SpanID(), start.Parent().SpanID())
+	assert.Contains(t, start.Attributes(), attrJobId.String("job"))
+
+	require.Len(t, spans["pogodoc.PollForJobCompletion"], 1)
+	poll := spans["pogodoc.PollForJobCompletion"][0]
+	assert.Equal(t, root.SpanContext().SpanID(), poll.Parent().SpanID())
+	assert.Contains(t, poll.Attributes(), attrRenderTime.Float64(1.5))
+	assert.Contains(t, poll.Attributes(), attrTarget.String("pdf"))
+
+	attempts := spans["pogodoc.PollAttempt"]
+	require.Len(t, attempts, 2)
+	for i, attempt := range attempts {
+		assert.Equal(t, poll.SpanContext().SpanID(), attempt.Parent().SpanID())
+		assert.Contains(t, attempt.Attributes(), attrPollAttempt.Int(i+1))
+	}
+
+	require.Len(t, spans["PUT"], 1)
+	upload := spans["PUT"][0]
+	assert.Equal(t, start.SpanContext().SpanID(), upload.Parent().SpanID())
+	assert.Contains(t, upload.Attributes(), attribute.String("url.full", server.URL+"/upload"))
+	assert.Contains(t, upload.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+
+	require.Len(t, spans["GET"], 2)
+	for _, get := range spans["GET"] {
+		assert.Contains(t, []string{attempts[0].SpanContext().SpanID().String(), attempts[1].SpanContext().SpanID().String()}, get.Parent().SpanID().String())
+	}
+	assert.Len(t, spans["POST"], 2)
+}
+
+func TestTracingRecordsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client, err := PogodocClientInitWithConfig(server.URL, "test-token",
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	require.NoError(t, err)
+
+	_, err = client.PollForJobCompletion("job", context.Background(), PollOptions{InitialDelay: -1})
+	require.Error(t, err)
+
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, codes.Error, span.Status().Code, span.Name())
+	}
+}
+
+// TestGeneratedClientDoesNotImportOpenTelemetry guards against tracing being
+// added to the generated client, which is overwritten by `fern generate`.
+// Spans for API calls are created by the middleware of the wrapper's transport.
+func TestGeneratedClientDoesNotImportOpenTelemetry(t *testing.T) {
+	err := filepath.WalkDir("client", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			require.NoError(t, err)
+			assert.False(t, strings.HasPrefix(importPath, "go.opentelemetry.io/"), "%s imports %s", path, importPath)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
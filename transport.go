@@ -0,0 +1,110 @@
+package pogodoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RoundTripperFunc adapts an ordinary function to the http.RoundTripper interface.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps every HTTP request issued by the SDK, including API calls,
+// uploads to presigned URLs and result downloads. Middleware runs once per
+// attempt, so retried requests pass through it again.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// defaultEndpoint names the API calls made without PogodocClient.Templates
+// and PogodocClient.Documents, through the embedded generated client.
+const defaultEndpoint = "api"
+
+// transport is the HTTPClient the generated client is built with, and the one
+// PogodocClient uses for presigned URLs. It retries failed requests, runs the
+// middleware around every attempt and logs and measures every request, so the
+// generated client only sends the request and decodes the response.
+type transport struct {
+	client HTTPClient
+	config *config
+}
+
+// callKey is the context key of the *call of an API request.
+type callKey struct{}
+
+// call describes an API call made through PogodocClient.Templates or
+// PogodocClient.Documents to the transport.
+type call struct {
+	// endpoint names the call in metrics, e.g. "documents.GetJobStatus".
+	endpoint string
+	// config overrides the client-wide configuration with the options of the call.
+	config *config
+}
+
+// withCall returns a copy of ctx carrying call.
+func withCall(ctx context.Context, call *call) context.Context {
+	return context.WithValue(ctx, callKey{}, call)
+}
+
+// Do implements HTTPClient for the generated client. Error responses are
+// returned as an *APIError once retries are exhausted, so the generated
+// client does not retry them again.
+func (t *transport) Do(req *http.Request) (*http.Response, error) {
+	endpoint, config := defaultEndpoint, t.config
+	if call, ok := req.Context().Value(callKey{}).(*call); ok {
+		endpoint, config = call.endpoint, call.config
+	}
+	resp, err := t.roundTrip(req, endpoint, config)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read error from response body: %w", err)
+		}
+		return nil, newAPIError(resp.StatusCode, resp.Header, body)
+	}
+	return resp, nil
+}
+
+// roundTrip sends req with retries. The endpoint names the request in metrics.
+// As with *http.Client.Do, error responses are returned as-is and it is the
+// caller's responsibility to check the status code and close the body.
+func (t *transport) roundTrip(req *http.Request, endpoint string, config *config) (*http.Response, error) {
+	var client HTTPClient = http.DefaultClient
+	if t.client != nil {
+		client = t.client
+	}
+	do := wrapMiddleware(client.Do, config.middleware)
+
+	logRequest(config.logger, req)
+	start := time.Now()
+	resp, err := config.retryRequest(do, req)
+	logResponse(config.logger, req, resp, err, start)
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	config.metrics.ObserveRequest(endpoint, statusCode, time.Since(start))
+	return resp, err
+}
+
+// wrapMiddleware wraps fn with the given middleware. The first middleware
+// is the outermost one, so it sees the request first and the response last.
+func wrapMiddleware(fn func(*http.Request) (*http.Response, error), middleware []Middleware) func(*http.Request) (*http.Response, error) {
+	if len(middleware) == 0 {
+		return fn
+	}
+	var next http.RoundTripper = RoundTripperFunc(fn)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	return next.RoundTrip
+}
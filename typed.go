@@ -0,0 +1,44 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ToDataMap converts a typed value into the map[string]interface{} form used by the
+// Data and SampleData fields of the API requests, following the value's json tags.
+func ToDataMap[T any](data T) (map[string]interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encoding data: %w", err)
+	}
+	var dataMap map[string]interface{}
+	if err := json.Unmarshal(raw, &dataMap); err != nil {
+		return nil, fmt.Errorf("data must encode to a JSON object: %w", err)
+	}
+	return dataMap, nil
+}
+
+// StartGenerateDocumentTyped starts an asynchronous document generation job with typed data.
+// The data is encoded with gdProps.DataEncoder (JSONEncoder by default) and replaces
+// any data set in gdProps.
+func StartGenerateDocumentTyped[T any](c *PogodocClient, gdProps GenerateDocumentProps, data T, ctx context.Context) (*string, error) {
+	gdProps.Data = data
+	return c.StartGenerateDocument(gdProps, ctx)
+}
+
+// GenerateDocumentTyped generates a document with typed data, like GenerateDocument.
+// The data is encoded with gdProps.DataEncoder (JSONEncoder by default) and replaces
+// any data set in gdProps.
+func GenerateDocumentTyped[T any](c *PogodocClient, gdProps GenerateDocumentProps, data T, ctx context.Context, opts ...PollOptions) (*GetJobStatusResponse, error) {
+	gdProps.Data = data
+	return c.GenerateDocument(gdProps, ctx, opts...)
+}
+
+// RenderImmediate renders a document with typed data, like GenerateDocumentImmediate.
+// The data must encode to a JSON object and replaces any data set in gdProps.
+func RenderImmediate[T any](c *PogodocClient, gdProps GenerateDocumentProps, data T, ctx context.Context) (*StartImmediateRenderResponse, error) {
+	gdProps.Data = data
+	return c.GenerateDocumentImmediate(gdProps, ctx)
+}
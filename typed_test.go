@@ -0,0 +1,143 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type customer struct {
+	Name    string   `json:"name"`
+	Address address  `json:"address"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+func TestToDataMap(t *testing.T) {
+	dataMap, err := ToDataMap(customer{Name: "John Doe", Address: address{City: "Skopje"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":    "John Doe",
+		"address": map[string]interface{}{"city": "Skopje"},
+	}, dataMap)
+
+	_, err = ToDataMap([]string{"not", "an", "object"})
+	require.Error(t, err)
+}
+
+func TestRenderImmediate(t *testing.T) {
+	var received StartImmediateRenderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/documents/immediate-render", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		assert.NoError(t, json.NewEncoder(w).Encode(StartImmediateRenderResponse{Url: "https://example.com/doc.pdf"}))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	response, err := RenderImmediate(client, GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			TemplateId: String("template"),
+			Type:       InitializeRenderJobRequestTypeHtml,
+			Target:     InitializeRenderJobRequestTargetPdf,
+		},
+	}, customer{Name: "John Doe", Address: address{City: "Skopje"}}, context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/doc.pdf", response.Url)
+	assert.Equal(t, "John Doe", received.Data["name"])
+	assert.Equal(t, map[string]interface{}{"city": "Skopje"}, received.Data["address"])
+}
+
+// csvEncoder is a DataEncoder with a non-JSON content type.
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return "text/csv" }
+
+func (csvEncoder) Encode(w io.Writer, data interface{}) error {
+	_, err := io.WriteString(w, "name\nJohn Doe\n")
+	return err
+}
+
+func TestGenerateDocumentImmediateRejectsData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	tests := []struct {
+		description string
+		data        interface{}
+		encoder     DataEncoder
+		wantError   string
+	}{
+		{
+			description: "array",
+			data:        []string{"not", "an", "object"},
+			wantError:   "immediate renders need data encoding to a JSON object, got an array",
+		},
+		{
+			description: "reader of an array",
+			data:        strings.NewReader(`[1, 2]`),
+			wantError:   "immediate renders need data encoding to a JSON object, got an array",
+		},
+		{
+			description: "invalid JSON reader",
+			data:        strings.NewReader(`{"name":`),
+			wantError:   "data is not valid JSON",
+		},
+		{
+			description: "custom encoding",
+			data:        map[string]interface{}{"name": "John Doe"},
+			encoder:     csvEncoder{},
+			wantError:   "immediate renders need JSON data, not text/csv",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := client.GenerateDocumentImmediate(GenerateDocumentProps{
+				InitializeRenderJobRequest: InitializeRenderJobRequest{
+					TemplateId: String("template"),
+					Type:       InitializeRenderJobRequestTypeHtml,
+					Target:     InitializeRenderJobRequestTargetPdf,
+				},
+				Data:        tc.data,
+				DataEncoder: tc.encoder,
+			}, context.Background())
+			var stepErr *StepError
+			require.ErrorAs(t, err, &stepErr)
+			assert.Equal(t, StepEncodeData, stepErr.Step)
+			assert.ErrorContains(t, err, tc.wantError)
+		})
+	}
+
+	t.Run("reader of an object", func(t *testing.T) {
+		var received StartImmediateRenderRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			assert.NoError(t, json.NewEncoder(w).Encode(StartImmediateRenderResponse{Url: "https://example.com/doc.pdf"}))
+		}))
+		defer server.Close()
+
+		_, err := newTestClient(t, server.URL).GenerateDocumentImmediate(GenerateDocumentProps{
+			InitializeRenderJobRequest: InitializeRenderJobRequest{
+				TemplateId: String("template"),
+				Type:       InitializeRenderJobRequestTypeHtml,
+				Target:     InitializeRenderJobRequestTargetPdf,
+			},
+			Data: strings.NewReader(`{"name":"John Doe"}`),
+		}, context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "John Doe", received.Data["name"])
+	})
+}
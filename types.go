@@ -1,6 +1,8 @@
 package pogodoc
 
 import (
+	"io"
+
 	api "github.com/Pogodoc/pogodoc-go/client"
 	"github.com/Pogodoc/pogodoc-go/client/client"
 	"github.com/Pogodoc/pogodoc-go/client/core"
@@ -8,16 +10,28 @@ import (
 )
 
 // PogodocClient is an interface wrapper for the generated client.
+// Its methods call the API through Templates and Documents, which shadow
+// the fields of the embedded generated client and can be substituted with
+// NewPogodocClient. When they are nil, as in a PogodocClient built as a struct
+// literal around a generated client, the endpoints of the generated client are used.
 type PogodocClient struct {
 	*client.Client
+
+	Templates TemplatesAPI
+	Documents DocumentsAPI
+
+	transport *transport
 }
 
 // FileStreamProps is a struct that holds the properties for file streams.
-// It contains the payload as a byte slice and its length.
+// It contains the payload, either as a byte slice or as a stream, and its length.
 // This struct is mainly used to pass file data when saving or updating templates.
+// Use FileStreamFromBytes, FileStreamFromReader or FileStreamFromFile to construct it.
 type FileStreamProps struct {
 	payload       []byte
 	payloadLength int
+	open          func() (io.ReadCloser, error)
+	progress      ProgressFunc
 }
 
 // GenerateDocumentProps is a struct that holds the properties for generating documents.
@@ -25,6 +39,17 @@ type GenerateDocumentProps struct {
 	InitializeRenderJobRequest InitializeRenderJobRequest
 	StartRenderJobRequest      StartRenderJobRequest
 	Template                   *string
+	// Data, if set, is rendered instead of InitializeRenderJobRequest.Data.
+	// It can be any value the DataEncoder accepts, such as a struct with json tags
+	// or a pre-encoded json.RawMessage.
+	Data interface{}
+	// DataEncoder encodes the data uploaded to the presigned data URL. Defaults to JSONEncoder.
+	DataEncoder DataEncoder
+	// UploadProgress, if set, reports the progress of the data and template uploads combined.
+	UploadProgress ProgressFunc
+	// DownloadProgress, if set, reports the progress of downloading the rendered output
+	// in GenerateDocumentToWriter and GenerateDocumentToFile.
+	DownloadProgress ProgressFunc
 }
 
 // Document Types
@@ -85,7 +110,6 @@ type BodyPropertiesOption = core.BodyPropertiesOption
 type QueryParametersOption = core.QueryParametersOption
 type MaxAttemptsOption = core.MaxAttemptsOption
 type TokenOption = core.TokenOption
-type APIError = core.APIError
 type FileParam = api.FileParam
 
 // Environment constants
@@ -1,8 +1,9 @@
 package pogodoc
 
 import (
-	"bytes"
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,37 +13,46 @@ import (
 // It takes the presigned URL, file stream properties, and content type as parameters.
 // The file stream properties include the payload (file content) and its length.
 // It returns an error if the upload fails or if required headers are missing.
+// Prefer PogodocClient.UploadToPresignedURL, which honors ctx and the client's HTTPClient.
 func UploadToS3WithURL(predsignedURL string, fsProps FileStreamProps, contentType string) error {
+	return (&PogodocClient{}).UploadToPresignedURL(context.Background(), predsignedURL, fsProps, contentType)
+}
 
-	headers := http.Header{}
-	if contentType != "" {
-		headers.Set("Content-Type", string(contentType))
-	} else {
+// UploadToPresignedURL uploads a file to a presigned URL on S3 using the HTTPClient configured on the client.
+// The upload is cancelled when ctx is done, and is retried on 408, 429 and 5xx responses
+// with the same back-off as the API calls. Any 2xx response is treated as success.
+func (c *PogodocClient) UploadToPresignedURL(ctx context.Context, presignedURL string, fsProps FileStreamProps, contentType string) error {
+	if contentType == "" {
 		return fmt.Errorf(" Content-Type is empty")
 	}
-
-	if fsProps.payloadLength > 0 {
-		headers.Set("Content-Length", fmt.Sprintf("%d", fsProps.payloadLength))
-	} else {
+	if fsProps.payloadLength <= 0 {
 		return fmt.Errorf(" Content-Length is empty")
 	}
-	client := &http.Client{}
 
-	req, err := http.NewRequest("PUT", predsignedURL, bytes.NewBuffer(fsProps.payload))
+	body, err := fsProps.body()
+	if err != nil {
+		return fmt.Errorf("opening file stream: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, presignedURL, body)
 	if err != nil {
-		return fmt.Errorf("creating request: %v", err)
+		body.Close()
+		return fmt.Errorf("creating request: %w", err)
 	}
-	req.Header = headers
+	req.ContentLength = fsProps.Size()
+	req.GetBody = fsProps.body
+	req.Header.Set("Content-Type", contentType)
 
-	resp, err := client.Do(req)
+	c.logger().DebugContext(ctx, "uploading file", slog.Int64("bytes", fsProps.Size()), slog.String("content_type", contentType))
+	resp, err := c.transfer(req, "upload")
 	if err != nil {
-		return fmt.Errorf("uploading file: %v", err)
+		return fmt.Errorf("%w: %w", ErrUploadFailed, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("uploading file: %s", resp.Status)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %w", ErrUploadFailed, newTransferError(resp))
 	}
+	c.metrics().AddUploadBytes(fsProps.Size())
 
 	return nil
 }
@@ -54,26 +64,16 @@ func UploadToS3WithURL(predsignedURL string, fsProps FileStreamProps, contentTyp
 func ReadFile(filePath string) ([]byte, error) {
 	absolutePath, err := filepath.Abs(filePath)
 	if err != nil {
-		fmt.Println("Error resolving absolute path:", err)
-		return nil, err
+		return nil, fmt.Errorf("resolving absolute path: %w", err)
 	}
 
-	file, err := os.Open(absolutePath)
-	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return nil, err
-	}
-	defer file.Close()
-
 	payload, err := os.ReadFile(absolutePath)
 	if err != nil {
-		fmt.Println("Error reading file:", err)
-		return nil, err
+		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
 	payloadLength := len(payload)
 	if payloadLength == 0 {
-		fmt.Println("Error: File is empty")
 		return nil, fmt.Errorf("error: File is empty")
 	}
 
@@ -83,6 +83,5 @@ func ReadFile(filePath string) ([]byte, error) {
 // Pointer is a utility function that returns a pointer to the given value.
 // It is a generic function that can take any type T and returns a pointer to T.
 func Pointer[T any](d T) *T {
-    return &d
+	return &d
 }
-
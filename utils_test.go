@@ -0,0 +1,159 @@
+package pogodoc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadToPresignedURL(t *testing.T) {
+	fsProps := FileStreamProps{
+		payload:       []byte("test data"),
+		payloadLength: 9,
+	}
+
+	t.Run("retries server errors and accepts any 2xx status", func(t *testing.T) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "application/zip", r.Header.Get("Content-Type"))
+			assert.Equal(t, int64(9), r.ContentLength)
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		httpClient := &countingClient{}
+		client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		require.NoError(t, client.UploadToPresignedURL(context.Background(), server.URL+"/upload", fsProps, "application/zip"))
+		assert.Equal(t, []string{"test data", "test data"}, bodies)
+		assert.Equal(t, int32(2), httpClient.calls.Load())
+	})
+
+	t.Run("reports client errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		err := UploadToS3WithURL(server.URL+"/upload", fsProps, "application/zip")
+		require.ErrorContains(t, err, "403")
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := newTestClient(t, server.URL).UploadToPresignedURL(ctx, server.URL+"/upload", fsProps, "application/zip")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestMiddlewareWrapsEveryRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("POST /documents/init", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(InitializeRenderJobResponse{
+			JobId:                  "job",
+			PresignedDataUploadUrl: String("http://" + r.Host + "/upload"),
+		}))
+	})
+	mux.HandleFunc("PUT /upload", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+	mux.HandleFunc("POST /documents/{jobId}/render", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(StartRenderJobResponse{JobId: r.PathValue("jobId")}))
+	})
+	mux.HandleFunc("GET /jobs/{jobId}", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(GetJobStatusResponse{
+			JobId:  r.PathValue("jobId"),
+			Status: "done",
+			Output: &GetJobStatusResponseOutput{
+				Data: &GetJobStatusResponseOutputData{Url: "http://" + r.Host + "/output.pdf"},
+			},
+		}))
+	})
+	mux.HandleFunc("GET /output.pdf", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("%PDF-1.7"))
+	})
+
+	var seen []string
+	recordRequests := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			seen = append(seen, req.Method+" "+req.URL.Path)
+			req.Header.Set("X-Trace-Id", "trace")
+			return next.RoundTrip(req)
+		})
+	}
+	requireTraceHeader := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "trace", req.Header.Get("X-Trace-Id"), "middleware should run in order")
+			return next.RoundTrip(req)
+		})
+	}
+
+	client, err := PogodocClientInitWithConfig(server.URL, "test-token", WithMiddleware(recordRequests), WithMiddleware(requireTraceHeader))
+	require.NoError(t, err)
+
+	var output strings.Builder
+	_, err = client.GenerateDocumentToWriter(GenerateDocumentProps{
+		InitializeRenderJobRequest: InitializeRenderJobRequest{
+			Type:   InitializeRenderJobRequestTypeHtml,
+			Target: InitializeRenderJobRequestTargetPdf,
+		},
+		Data: map[string]interface{}{"name": "Ada"},
+	}, &output, context.Background(), PollOptions{InitialDelay: -1, Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7", output.String())
+	assert.Equal(t, []string{
+		"POST /documents/init",
+		"PUT /upload",
+		"POST /documents/job/render",
+		"GET /jobs/job",
+		"GET /output.pdf",
+	}, seen)
+}
+
+func TestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	var output strings.Builder
+	logger := slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := PogodocClientInitWithConfig(server.URL, "secret-token", WithLogger(logger))
+	require.NoError(t, err)
+
+	err = client.UploadToPresignedURL(context.Background(), server.URL+"/upload?X-Amz-Signature=secret-signature", FileStreamFromBytes([]byte("test data")), "application/zip")
+	require.NoError(t, err)
+
+	logs := output.String()
+	assert.Contains(t, logs, `msg="uploading file" bytes=9 content_type=application/zip`)
+	assert.Contains(t, logs, "X-Amz-Signature=REDACTED")
+	assert.NotContains(t, logs, "secret")
+}
@@ -3,67 +3,125 @@ package pogodoc
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/Pogodoc/pogodoc-go/pogodoctest"
 	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests of this file replay the API interactions recorded in
+// testdata/cassettes. Tests without a cassette run against a pogodoctest
+// server instead. Set POGODOC_RECORD=api to record the cassettes against the
+// API configured in .env (POGODOC_BASE_URL, POGODOC_API_TOKEN and TEMPLATE_ID).
+
+const (
+	templatePath   = "testdata/templates/react-template.zip"
+	sampleDataPath = "testdata/json_data/react.json"
 )
 
 type PogodocEnv struct {
-	baseURL string `env:"LAMBDA_BASE_URL"`
-	token   string `env:"POGODOC_API_TOKEN"`
+	baseURL    string
+	token      string
+	templateId string
 }
 
 type TestData struct {
 	PogodocEnv    PogodocEnv
 	client        PogodocClient
+	httpClient    RequestOption
 	ctx           context.Context
+	pollOptions   PollOptions
 	sampleDataMap map[string]interface{}
 }
 
-func PrepareData() TestData {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
-	pogodocEnv := PogodocEnv{
-		baseURL: os.Getenv("LAMBDA_BASE_URL"),
-		token:   os.Getenv("POGODOC_API_TOKEN"),
-	}
-	c, err := PogodocClientInit()
-	if err != nil {
-		fmt.Println("Error initializing PogodocClient")
-		return TestData{}
-	}
-	ctx := context.Background()
+// newTestEnv returns the environment of the test and the HTTP client to reach
+// it with: the API recorded to the test's cassette when POGODOC_RECORD=api,
+// the replayed cassette if there is one, and a pogodoctest server otherwise.
+// It reports whether requests are served without the API, so jobs need not
+// be polled at the pace of real renders.
+func newTestEnv(t *testing.T) (PogodocEnv, HTTPClient, bool) {
+	path := filepath.Join("testdata", "cassettes", t.Name()+".json")
+	var pogodocEnv PogodocEnv
+	mode := pogodoctest.ModeReplay
+
+	if os.Getenv("POGODOC_RECORD") == "api" {
+		require.NoError(t, godotenv.Load(), "Error loading .env file")
+		pogodocEnv = PogodocEnv{
+			baseURL:    os.Getenv("POGODOC_BASE_URL"),
+			token:      os.Getenv("POGODOC_API_TOKEN"),
+			templateId: os.Getenv("TEMPLATE_ID"),
+		}
+		if pogodocEnv.baseURL == "" {
+			pogodocEnv.baseURL = Environments.Default
+		}
+		mode = pogodoctest.ModeRecord
+	} else if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		server := pogodoctest.NewServer()
+		t.Cleanup(server.Close)
+		templateId := server.AddTemplate(pogodoctest.Template{Type: "html", IndexHtml: "<h1>Hello <%= name %></h1>"})
+		return PogodocEnv{baseURL: server.URL, token: "test-token", templateId: templateId}, server.Client(), true
+	} else {
+		pogodocEnv.token = "test-token"
+	}
+
+	recorder, err := pogodoctest.NewRecorder(path, mode)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, recorder.Save())
+		assert.Empty(t, recorder.Unplayed(), "recorded interactions were not replayed")
+	})
+
+	pogodocEnv.baseURL = recorder.Var("baseURL", pogodocEnv.baseURL)
+	pogodocEnv.templateId = recorder.Var("templateId", pogodocEnv.templateId)
+	return pogodocEnv, recorder.Client(), mode == pogodoctest.ModeReplay
+}
 
-	sampledata, _ := ReadFile("../../data/json_data/react.json")
+func PrepareData(t *testing.T) TestData {
+	pogodocEnv, client, offline := newTestEnv(t)
 
+	sampledata, err := ReadFile(sampleDataPath)
+	require.NoError(t, err, "Error reading sample data")
 	var sampleDataMap map[string]interface{}
-	_ = json.Unmarshal([]byte(sampledata), &sampleDataMap)
+	require.NoError(t, json.Unmarshal(sampledata, &sampleDataMap))
+
+	httpClient := WithHTTPClient(client)
+
+	c, err := PogodocClientInitWithConfig(pogodocEnv.baseURL, pogodocEnv.token, httpClient)
+	require.NoError(t, err, "Error initializing PogodocClient")
+
+	var pollOptions PollOptions
+	if offline {
+		pollOptions = PollOptions{InitialDelay: -1, Interval: time.Millisecond}
+	}
 
 	return TestData{
 		PogodocEnv:    pogodocEnv,
 		client:        *c,
-		ctx:           ctx,
+		httpClient:    httpClient,
+		ctx:           context.Background(),
+		pollOptions:   pollOptions,
 		sampleDataMap: sampleDataMap,
 	}
 }
 
 func TestPogodocClient(t *testing.T) {
-	data := PrepareData()
-	_, err := PogodocClientInitWithConfig(data.PogodocEnv.baseURL, data.PogodocEnv.token)
+	_, err := PogodocClientInitWithConfig(Environments.Default, "test-token")
 	if err != nil {
 		t.Errorf("PogodocClientInit failed: %v", err)
 	}
 }
 
 func TestSaveTemplate(t *testing.T) {
-	data := PrepareData()
+	data := PrepareData(t)
 
-	_, err := data.client.SaveTemplate("../../data/templates/React-Demo-App.zip", SaveCreatedTemplateRequestTemplateInfo{
+	_, err := data.client.SaveTemplate(templatePath, SaveCreatedTemplateRequestTemplateInfo{
 		Title:       "Naslov",
 		Description: "Deksripshn",
 		Type:        SaveCreatedTemplateRequestTemplateInfoTypeReact,
@@ -77,9 +135,9 @@ func TestSaveTemplate(t *testing.T) {
 }
 
 func TestUpdateTemplate(t *testing.T) {
-	data := PrepareData()
+	data := PrepareData(t)
 	templateId, err := data.client.SaveTemplate(
-		"../../data/templates/React-Demo-App.zip",
+		templatePath,
 		SaveCreatedTemplateRequestTemplateInfo{
 			Title:       "Naslov",
 			Description: "Deksripshn",
@@ -96,7 +154,7 @@ func TestUpdateTemplate(t *testing.T) {
 	src := "SORSKODE"
 	_, err = data.client.UpdateTemplate(
 		templateId,
-		"../../data/templates/React-Demo-App.zip",
+		templatePath,
 		UpdateTemplateRequestTemplateInfo{
 			Title:       "Naslov SMENET",
 			Description: "ANDREJ UPDATE TEMPLATE",
@@ -113,7 +171,7 @@ func TestUpdateTemplate(t *testing.T) {
 }
 
 func TestGenerateDocument(t *testing.T) {
-	data := PrepareData()
+	data := PrepareData(t)
 
 	sampleData := make(map[string]interface{})
 
@@ -128,7 +186,7 @@ func TestGenerateDocument(t *testing.T) {
 
 	simpleDocumentProps := GenerateDocumentProps{
 		InitializeRenderJobRequest: InitializeRenderJobRequest{
-			TemplateId: String(os.Getenv("TEMPLATE_ID")),
+			TemplateId: String(data.PogodocEnv.templateId),
 			Type:       InitializeRenderJobRequestTypeHtml,
 			Target:     InitializeRenderJobRequestTargetPdf,
 			Data:       sampleData,
@@ -136,33 +194,28 @@ func TestGenerateDocument(t *testing.T) {
 	}
 
 	jobId, err := data.client.StartGenerateDocument(simpleDocumentProps, data.ctx)
-	fmt.Println("START GENERATE DOCUMENT: ", *jobId)
-	if err != nil {
-		t.Errorf("GenerateDocument failed: %v", err)
-	}
+	require.NoError(t, err, "StartGenerateDocument failed")
+	t.Log("START GENERATE DOCUMENT: ", *jobId)
 
-	generatedDocument, err := data.client.GenerateDocument(simpleDocumentProps, data.ctx)
-	fmt.Println("GENERATE DOCUMENT: ", generatedDocument.Output.Data.Url)
-	if err != nil {
-		t.Errorf("GenerateDocument failed: %v", err)
-	}
+	generatedDocument, err := data.client.GenerateDocument(simpleDocumentProps, data.ctx, data.pollOptions)
+	require.NoError(t, err, "GenerateDocument failed")
+	t.Log("GENERATE DOCUMENT: ", generatedDocument.Output.Data.Url)
 
 	immediateDocument, err := data.client.GenerateDocumentImmediate(simpleDocumentProps, data.ctx)
-	fmt.Println("GENERATE DOCUMENT IMMEDIATE: ", immediateDocument.Url)
-	if err != nil {
-		t.Errorf("GenerateDocumentImmediate failed: %v", err)
-	}
+	require.NoError(t, err, "GenerateDocumentImmediate failed")
+	t.Log("GENERATE DOCUMENT IMMEDIATE: ", immediateDocument.Url)
 }
 
 func TestReadMeExample(t *testing.T) {
-	godotenv.Load()
+	data := PrepareData(t)
+	if os.Getenv("POGODOC_RECORD") != "api" {
+		t.Setenv("POGODOC_BASE_URL", data.PogodocEnv.baseURL)
+		t.Setenv("POGODOC_API_TOKEN", data.PogodocEnv.token)
+	}
 	ctx := context.Background()
 
-	client, err := PogodocClientInit()
-
-	if err != nil {
-		t.Errorf("PogodocClientInit failed: %v", err)
-	}
+	client, err := PogodocClientInit(data.httpClient)
+	require.NoError(t, err, "PogodocClientInit failed")
 
 	var sampleData map[string]interface{}
 
@@ -177,17 +230,13 @@ func TestReadMeExample(t *testing.T) {
 
 	documentProps := GenerateDocumentProps{
 		InitializeRenderJobRequest: InitializeRenderJobRequest{
-			TemplateId: String(os.Getenv("TEMPLATE_ID")),
+			TemplateId: String(data.PogodocEnv.templateId),
 			Type:       InitializeRenderJobRequestType("html"),
 			Target:     InitializeRenderJobRequestTarget("pdf"),
 			Data:       sampleData,
 		},
 	}
 
-	doc, err := client.GenerateDocument(documentProps, ctx)
-	if err != nil {
-		t.Errorf("GenerateDocument failed: %v", err)
-	}
-
-	fmt.Println("README EXAMPLE: ", doc.Output.Data.Url)
-}
\ No newline at end of file
+	_, err = client.GenerateDocument(documentProps, ctx, data.pollOptions)
+	require.NoError(t, err, "GenerateDocument failed")
+}